@@ -0,0 +1,83 @@
+package stopwatch
+
+import (
+	"time"
+)
+
+// Adjustment is an audit entry of AddElapsed and SubtractElapsed
+type Adjustment struct {
+	Time  time.Time     `json:"time"`
+	Delta time.Duration `json:"delta"`
+}
+
+// AddElapsed makes the elapsed time and the current lap longer by d,
+// e.g. to count work done before the stopwatch was created. See Adjustments.
+func (s *Stopwatch) AddElapsed(d time.Duration) {
+	s.adjustAt(s.now(), d)
+}
+
+// SubtractElapsed makes the elapsed time and the current lap shorter by d, e.g. to credit back
+// time spent waiting for a paused external dependency. The current lap can't get negative,
+// so no more than the current lap time is subtracted. See Adjustments.
+func (s *Stopwatch) SubtractElapsed(d time.Duration) {
+	s.adjustAt(s.now(), -d)
+}
+
+// SyncTo re-anchors the stopwatch to an authoritative clock, like a game server:
+// the elapsed time at ref was elapsed. Lap history is kept, the correction goes to the current
+// lap and is recorded in Adjustments. As with SubtractElapsed, the current lap can't get negative.
+func (s *Stopwatch) SyncTo(ref time.Time, elapsed time.Duration) {
+	s.lock()
+	// the drift is applied under the same lock, so a concurrent change can't be counted twice
+	event, applied := s.adjustLocked(ref, elapsed-s.ElapsedTimeFrom(ref))
+	log := s.eventLog
+	s.unlock()
+
+	if applied && log != nil {
+		log(event)
+	}
+}
+
+// SetElapsed sets the elapsed time, e.g. when a stopwatch continues timing started by
+// another system of record. It's SyncTo the current time.
+func (s *Stopwatch) SetElapsed(d time.Duration) {
+	s.SyncTo(s.now(), d)
+}
+
+// Adjustments returns adjustments of the elapsed time since the last Reset
+func (s *Stopwatch) Adjustments() []Adjustment {
+	s.rlock()
+	defer s.runlock()
+	return append([]Adjustment(nil), s.adjustments...)
+}
+
+func (s *Stopwatch) adjustAt(now time.Time, d time.Duration) {
+	s.lock()
+	event, applied := s.adjustLocked(now, d)
+	log := s.eventLog
+	s.unlock()
+
+	if applied && log != nil {
+		log(event)
+	}
+}
+
+// adjustLocked must be called under the lock, it reports whether the adjustment was applied
+func (s *Stopwatch) adjustLocked(now time.Time, d time.Duration) (Event, bool) {
+	if min := s.mark - s.ElapsedTimeFrom(now); d < min {
+		d = min
+	}
+	event := Event{Kind: EventAdjust, Time: now, Delta: d}
+	applied := d != 0 && s.walWrite(event)
+	if applied {
+		s.start = s.start.Add(-d)
+		s.adjusted += d
+		s.adjustments = append(s.adjustments, Adjustment{Time: now, Delta: d})
+	}
+	return event, applied
+}
+
+// startedAtLocked is the real start time, start is shifted by pauses and adjustments
+func (s *Stopwatch) startedAtLocked() time.Time {
+	return s.start.Add(s.adjusted - s.paused)
+}
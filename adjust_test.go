@@ -0,0 +1,113 @@
+package stopwatch
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdjustElapsed(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, false)
+	sw.start = start
+	sw.stop = start.Add(30 * time.Millisecond)
+
+	sw.AddElapsed(10 * time.Millisecond)
+	assert.Equal(t, 40*time.Millisecond, sw.ElapsedTime())
+	sw.SubtractElapsed(15 * time.Millisecond)
+	assert.Equal(t, 25*time.Millisecond, sw.ElapsedTime())
+
+	adjustments := sw.Adjustments()
+	assert.Len(t, adjustments, 2)
+	assert.Equal(t, 10*time.Millisecond, adjustments[0].Delta)
+	assert.Equal(t, -15*time.Millisecond, adjustments[1].Delta)
+
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	assert.Contains(t, sw.String(), `"started_at":"2021-01-02T03:04:05Z"`, "the real start is kept")
+	assert.Contains(t, sw.String(), `"adjusted_ms":-5`)
+
+	sw.Reset(0, true)
+	assert.Empty(t, sw.Adjustments())
+}
+
+func TestSubtractElapsedKeepsLapPositive(t *testing.T) {
+	sw := New(0, false)
+	sw.stop = sw.start.Add(30 * time.Millisecond)
+	sw.LapWithDataAndTime(sw.stop, "lap1", nil)
+
+	sw.SubtractElapsed(time.Second)
+	assert.Empty(t, sw.Adjustments(), "the current lap is empty")
+	assert.Equal(t, 30*time.Millisecond, sw.ElapsedTime())
+}
+
+func TestAdjustElapsedReplay(t *testing.T) {
+	var events []Event
+	sw := New(0, true)
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+	sw.AddElapsed(time.Second)
+	sw.Lap("lap1")
+	sw.Stop()
+
+	replayed, err := Replay(events)
+	assert.NoError(t, err)
+	assert.True(t, Equal(sw, replayed, 0))
+	assert.Equal(t, sw.Adjustments(), replayed.Adjustments())
+}
+
+func TestSyncTo(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, true)
+	sw.start = start
+	sw.LapWithDataAndTime(start.Add(10*time.Millisecond), "lap1", nil)
+
+	// the server says 25ms passed by the moment our clock shows 20ms
+	sw.SyncTo(start.Add(20*time.Millisecond), 25*time.Millisecond)
+	assert.Equal(t, 25*time.Millisecond, sw.ElapsedTimeFrom(start.Add(20*time.Millisecond)))
+	assert.Equal(t, 5*time.Millisecond, sw.Adjustments()[0].Delta)
+	assert.Equal(t, 10*time.Millisecond, sw.Laps()[0].Duration(), "history is kept")
+
+	lap := sw.LapWithDataAndTime(start.Add(30*time.Millisecond), "lap2", nil)
+	assert.Equal(t, 25*time.Millisecond, lap.Duration())
+
+	sw.Stop()
+	elapsed := sw.ElapsedTime()
+	sw.SyncTo(time.Now(), elapsed-time.Millisecond)
+	assert.Equal(t, elapsed-time.Millisecond, sw.ElapsedTime(), "stopped")
+}
+
+func TestSyncToConcurrent(t *testing.T) {
+	sw := New(0, false)
+	ref := time.Now()
+
+	var wg sync.WaitGroup
+	ready := make(chan struct{})
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ready
+			sw.SyncTo(ref, time.Hour)
+		}()
+	}
+	close(ready)
+	wg.Wait()
+
+	assert.Equal(t, time.Hour, sw.ElapsedTime(), "the drift is applied once")
+	assert.Len(t, sw.Adjustments(), 1)
+}
+
+func TestSetElapsed(t *testing.T) {
+	sw := New(0, false)
+	sw.SetElapsed(time.Hour)
+	assert.Equal(t, time.Hour, sw.ElapsedTime())
+
+	sw.Start()
+	lap := sw.Lap("imported")
+	assert.True(t, lap.Duration() >= time.Hour)
+
+	sw = New(0, true)
+	sw.SetElapsed(time.Minute)
+	assert.InDelta(t, float64(time.Minute), float64(sw.ElapsedTime()), float64(time.Second))
+}
@@ -0,0 +1,58 @@
+package stopwatch
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnexpectedStateKey is the lap data key flagging laps with a state not allowed by SetAllowedStates
+const UnexpectedStateKey = "unexpected_state"
+
+// ErrUnexpectedState is returned by CheckState for a state not allowed by SetAllowedStates
+var ErrUnexpectedState = errors.New("unexpected lap state")
+
+// SetAllowedStates declares the only expected lap states, so typos in state names
+// don't silently fragment dashboards. Laps with other states are still recorded,
+// but flagged with UnexpectedStateKey set to true in the lap data.
+// Calling it without states allows any state.
+func (s *Stopwatch) SetAllowedStates(states ...string) {
+	s.lock()
+	defer s.unlock()
+	if len(states) == 0 {
+		s.allowedStates = nil
+		return
+	}
+	s.allowedStates = make(map[string]struct{}, len(states))
+	for _, state := range states {
+		s.allowedStates[state] = struct{}{}
+	}
+}
+
+// CheckState returns an error wrapping ErrUnexpectedState if the state is not allowed
+func (s *Stopwatch) CheckState(state string) error {
+	s.rlock()
+	defer s.runlock()
+	if !s.stateAllowed(state) {
+		return fmt.Errorf("%w %q", ErrUnexpectedState, state)
+	}
+	return nil
+}
+
+// stateAllowed must be called under the lock
+func (s *Stopwatch) stateAllowed(state string) bool {
+	if s.allowedStates == nil {
+		return true
+	}
+	_, found := s.allowedStates[state]
+	return found
+}
+
+// flagUnexpectedState must be called under the lock
+func (s *Stopwatch) flagUnexpectedState(state string, data map[string]interface{}) map[string]interface{} {
+	if s.stateAllowed(state) {
+		return data
+	}
+	data = copyData(data, 1)
+	data[UnexpectedStateKey] = true
+	return data
+}
@@ -0,0 +1,29 @@
+package stopwatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedStates(t *testing.T) {
+	sw := New(0, true)
+	sw.SetAllowedStates("parse", "plan", "exec")
+
+	sw.Lap("parse")
+	sw.Lap("exce")
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 2)
+	assert.Nil(t, laps[0].data)
+	assert.Equal(t, map[string]interface{}{UnexpectedStateKey: true}, laps[1].data)
+
+	assert.NoError(t, sw.CheckState("plan"))
+	err := sw.CheckState("exce")
+	assert.True(t, errors.Is(err, ErrUnexpectedState))
+	assert.EqualError(t, err, `unexpected lap state "exce"`)
+
+	sw.SetAllowedStates()
+	assert.NoError(t, sw.CheckState("exce"))
+}
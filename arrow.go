@@ -0,0 +1,390 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+)
+
+// Arrow IPC metadata enums, see Schema.fbs and Message.fbs of the Arrow format
+const (
+	arrowMetadataV5      = 4
+	arrowHeaderSchema    = 1
+	arrowHeaderRecords   = 3
+	arrowTypeInt         = 2
+	arrowTypeFloat       = 3
+	arrowTypeUtf8        = 5
+	arrowTypeTimestamp   = 10
+	arrowDoublePrecision = 2
+	arrowMicroseconds    = 2
+)
+
+// arrowContinuation starts every message of an Arrow IPC stream
+const arrowContinuation = 0xFFFFFFFF
+
+// WriteArrow writes laps to w as an Arrow IPC stream with a single record batch, with the
+// schema of WriteParquet: state, started_at (timestamp, µs, UTC), offset_ms, duration_ms,
+// correlation_id, run_id and data as JSON. Columns are not nullable, optional values
+// are empty strings. The stream is read by pyarrow.ipc.open_stream, ipc.NewReader of
+// the Arrow Go module or DuckDB without intermediate JSON. See Columns for in-process use.
+func (s *Stopwatch) WriteArrow(w io.Writer) error {
+	laps := s.Laps()
+	state := newArrowColumn("state", arrowTypeUtf8)
+	startedAt := newArrowColumn("started_at", arrowTypeTimestamp)
+	offset := newArrowColumn("offset_ms", arrowTypeFloat)
+	duration := newArrowColumn("duration_ms", arrowTypeFloat)
+	correlationID := newArrowColumn("correlation_id", arrowTypeUtf8)
+	runID := newArrowColumn(RunIDKey, arrowTypeUtf8)
+	data := newArrowColumn("data", arrowTypeUtf8)
+
+	for _, lap := range laps {
+		state.string(lap.state)
+		startedAt.int64(lap.end.Add(-lap.duration).UnixNano() / 1000)
+		offset.double(milliseconds(lap.offset))
+		duration.double(milliseconds(lap.duration))
+		correlationID.string(lap.correlationID)
+		runID.string(lap.runID)
+		encoded := ""
+		if len(lap.data) > 0 {
+			b, err := json.Marshal(lap.data)
+			if err != nil {
+				return err
+			}
+			encoded = string(b)
+		}
+		data.string(encoded)
+	}
+	return writeArrow(w, len(laps), state, startedAt, offset, duration, correlationID, runID, data)
+}
+
+// WriteArrowSummary writes a row per lap state to w as an Arrow IPC stream, with the
+// schema of WriteParquetSummary: state, count, total_ms and mean_ms, sorted by state
+func (s *Stopwatch) WriteArrowSummary(w io.Writer) error {
+	counts := s.StateCounts()
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	state := newArrowColumn("state", arrowTypeUtf8)
+	count := newArrowColumn("count", arrowTypeInt)
+	total := newArrowColumn("total_ms", arrowTypeFloat)
+	mean := newArrowColumn("mean_ms", arrowTypeFloat)
+	for _, name := range states {
+		c := counts[name]
+		state.string(name)
+		count.int64(int64(c.Count))
+		total.double(milliseconds(c.Total))
+		mean.double(milliseconds(c.Total) / float64(c.Count))
+	}
+	return writeArrow(w, len(states), state, count, total, mean)
+}
+
+// arrowColumn is a not nullable column, values of utf8 columns follow their offsets
+type arrowColumn struct {
+	name    string
+	kind    byte
+	offsets []byte
+	values  bytes.Buffer
+}
+
+func newArrowColumn(name string, kind byte) *arrowColumn {
+	c := &arrowColumn{name: name, kind: kind}
+	if kind == arrowTypeUtf8 {
+		c.offsets = make([]byte, 4)
+	}
+	return c
+}
+
+func (c *arrowColumn) int64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	c.values.Write(b[:])
+}
+
+func (c *arrowColumn) double(v float64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	c.values.Write(b[:])
+}
+
+func (c *arrowColumn) string(v string) {
+	c.values.WriteString(v)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(c.values.Len()))
+	c.offsets = append(c.offsets, b[:]...)
+}
+
+// fieldType returns the Type union of the column
+func (c *arrowColumn) fieldType() *fbTable {
+	switch c.kind {
+	case arrowTypeFloat:
+		return &fbTable{fields: []fbField{fbInt16(0, arrowDoublePrecision)}}
+	case arrowTypeInt:
+		return &fbTable{fields: []fbField{fbInt32(0, 64), fbBool(1, true)}}
+	case arrowTypeTimestamp:
+		return &fbTable{fields: []fbField{fbInt16(0, arrowMicroseconds), fbOffset(1, fbString("UTC"))}}
+	default:
+		return &fbTable{}
+	}
+}
+
+// writeArrow writes a stream of the schema, a single record batch and the end of stream marker
+func writeArrow(w io.Writer, rows int, columns ...*arrowColumn) error {
+	fields := make(fbTables, len(columns))
+	for i, c := range columns {
+		fields[i] = &fbTable{fields: []fbField{
+			fbOffset(0, fbString(c.name)),
+			fbBool(1, false),
+			fbUint8(2, c.kind),
+			fbOffset(3, c.fieldType()),
+			fbOffset(5, fbTables{}),
+		}}
+	}
+	schema := &fbTable{fields: []fbField{fbInt16(0, 0), fbOffset(1, fields)}} // little endian
+
+	// every column has an empty validity buffer, utf8 columns have offsets before values
+	var body bytes.Buffer
+	var nodes, buffers []byte
+	addBuffer := func(data []byte) {
+		buffers = appendInt64(buffers, int64(body.Len()))
+		buffers = appendInt64(buffers, int64(len(data)))
+		body.Write(data)
+		for body.Len()%8 != 0 {
+			body.WriteByte(0)
+		}
+	}
+	for _, c := range columns {
+		nodes = appendInt64(nodes, int64(rows))
+		nodes = appendInt64(nodes, 0)
+		addBuffer(nil)
+		if c.kind == arrowTypeUtf8 {
+			addBuffer(c.offsets)
+		}
+		addBuffer(c.values.Bytes())
+	}
+	records := &fbTable{fields: []fbField{
+		fbInt64(0, int64(rows)),
+		fbOffset(1, fbStructs{size: 16, data: nodes}),
+		fbOffset(2, fbStructs{size: 16, data: buffers}),
+	}}
+
+	var stream bytes.Buffer
+	writeArrowMessage(&stream, arrowHeaderSchema, schema, nil)
+	writeArrowMessage(&stream, arrowHeaderRecords, records, body.Bytes())
+	stream.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0})
+
+	_, err := w.Write(stream.Bytes())
+	return err
+}
+
+// writeArrowMessage writes the continuation marker, the size of the message metadata
+// padded to 8 bytes, the metadata and the body
+func writeArrowMessage(stream *bytes.Buffer, headerType byte, header *fbTable, body []byte) {
+	message := &fbTable{fields: []fbField{
+		fbInt16(0, arrowMetadataV5),
+		fbUint8(1, headerType),
+		fbOffset(2, header),
+		fbInt64(3, int64(len(body))),
+	}}
+	metadata := fbFinish(message)
+	for len(metadata)%8 != 0 {
+		metadata = append(metadata, 0)
+	}
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:4], arrowContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(metadata)))
+	stream.Write(prefix[:])
+	stream.Write(metadata)
+	stream.Write(body)
+}
+
+func appendInt64(buf []byte, v int64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	return append(buf, b[:]...)
+}
+
+// fbTable is a flatbuffers table. Tables are written front to back: the vtable, the table,
+// then the objects it refers to, so offsets to objects point forward as flatbuffers requires.
+type fbTable struct {
+	fields []fbField
+}
+
+// fbField is a table field in the vtable slot, either an inline scalar or an offset to an object
+type fbField struct {
+	slot   int
+	scalar []byte
+	object fbObject
+}
+
+// fbObject is an object a table refers to, written after the table
+type fbObject interface {
+	writeTo(b *fbBuilder) int
+}
+
+// fbString is a flatbuffers string
+type fbString string
+
+// fbTables is a vector of tables
+type fbTables []*fbTable
+
+// fbStructs is a vector of structs of size bytes, aligned to 8 bytes
+type fbStructs struct {
+	size int
+	data []byte
+}
+
+func fbUint8(slot int, v byte) fbField {
+	return fbField{slot: slot, scalar: []byte{v}}
+}
+
+func fbBool(slot int, v bool) fbField {
+	if v {
+		return fbUint8(slot, 1)
+	}
+	return fbUint8(slot, 0)
+}
+
+func fbInt16(slot int, v int16) fbField {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(v))
+	return fbField{slot: slot, scalar: b}
+}
+
+func fbInt32(slot int, v int32) fbField {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(v))
+	return fbField{slot: slot, scalar: b}
+}
+
+func fbInt64(slot int, v int64) fbField {
+	return fbField{slot: slot, scalar: appendInt64(nil, v)}
+}
+
+func fbOffset(slot int, object fbObject) fbField {
+	return fbField{slot: slot, object: object}
+}
+
+// fbBuilder holds a flatbuffer being written, positions are offsets from its start
+type fbBuilder struct {
+	buf []byte
+}
+
+// fbFinish returns the buffer with root as the root table
+func fbFinish(root *fbTable) []byte {
+	b := &fbBuilder{buf: make([]byte, 4)}
+	b.putUint32(0, uint32(root.writeTo(b)))
+	return b.buf
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) putUint32(pos int, v uint32) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], v)
+}
+
+func (b *fbBuilder) appendUint32(v uint32) {
+	b.buf = append(b.buf, 0, 0, 0, 0)
+	b.putUint32(len(b.buf)-4, v)
+}
+
+// patch points the offset at pos to the object written at target
+func (b *fbBuilder) patch(pos, target int) {
+	b.putUint32(pos, uint32(target-pos))
+}
+
+func (t *fbTable) writeTo(b *fbBuilder) int {
+	// inline fields go by size, largest first, so each one is aligned to its size
+	fields := make([]fbField, len(t.fields))
+	copy(fields, t.fields)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].inlineSize() > fields[j].inlineSize()
+	})
+	slots := 0
+	inline := make([]int, len(fields))
+	size := 4 // the offset to the vtable
+	for i, field := range fields {
+		n := field.inlineSize()
+		for size%n != 0 {
+			size++
+		}
+		inline[i] = size
+		size += n
+		if field.slot >= slots {
+			slots = field.slot + 1
+		}
+	}
+
+	vtable := make([]byte, 4+2*slots)
+	binary.LittleEndian.PutUint16(vtable, uint16(len(vtable)))
+	binary.LittleEndian.PutUint16(vtable[2:], uint16(size))
+	for i, field := range fields {
+		binary.LittleEndian.PutUint16(vtable[4+2*field.slot:], uint16(inline[i]))
+	}
+	b.align(2)
+	vtablePos := len(b.buf)
+	b.buf = append(b.buf, vtable...)
+
+	b.align(8)
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	b.putUint32(pos, uint32(pos-vtablePos))
+	for i, field := range fields {
+		if field.object == nil {
+			copy(b.buf[pos+inline[i]:], field.scalar)
+		}
+	}
+	for i, field := range fields {
+		if field.object != nil {
+			b.patch(pos+inline[i], field.object.writeTo(b))
+		}
+	}
+	return pos
+}
+
+func (f fbField) inlineSize() int {
+	if f.object != nil {
+		return 4
+	}
+	return len(f.scalar)
+}
+
+func (s fbString) writeTo(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.appendUint32(uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+func (v fbTables) writeTo(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.appendUint32(uint32(len(v)))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, table := range v {
+		b.patch(pos+4+4*i, table.writeTo(b))
+	}
+	return pos
+}
+
+func (v fbStructs) writeTo(b *fbBuilder) int {
+	for len(b.buf)%8 != 4 {
+		b.buf = append(b.buf, 0)
+	}
+	pos := len(b.buf)
+	b.appendUint32(uint32(len(v.data) / v.size))
+	b.buf = append(b.buf, v.data...)
+	return pos
+}
@@ -0,0 +1,158 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fbReader reads fields of flatbuffers tables, to check the Arrow metadata
+type fbReader struct {
+	buf []byte
+}
+
+func (r fbReader) uint32(pos int) int {
+	return int(binary.LittleEndian.Uint32(r.buf[pos:]))
+}
+
+// field returns the position of a field of the table at pos, zero if it's absent
+func (r fbReader) field(table, slot int) int {
+	vtable := table - int(int32(r.uint32(table)))
+	if 4+2*slot >= int(binary.LittleEndian.Uint16(r.buf[vtable:])) {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(r.buf[vtable+4+2*slot:]))
+	if offset == 0 {
+		return 0
+	}
+	return table + offset
+}
+
+// object follows the offset of a field
+func (r fbReader) object(table, slot int) int {
+	pos := r.field(table, slot)
+	return pos + r.uint32(pos)
+}
+
+func (r fbReader) string(pos int) string {
+	return string(r.buf[pos+4 : pos+4+r.uint32(pos)])
+}
+
+func (r fbReader) int64(pos int) int64 {
+	return int64(binary.LittleEndian.Uint64(r.buf[pos:]))
+}
+
+// readArrowMessages splits an Arrow IPC stream into messages, returning the metadata,
+// the position of the header table and the body of each message
+func readArrowMessages(t *testing.T, stream []byte) ([]fbReader, []int, [][]byte) {
+	var metadata []fbReader
+	var headers []int
+	var bodies [][]byte
+	for {
+		assert.Equal(t, uint32(arrowContinuation), binary.LittleEndian.Uint32(stream))
+		size := int(binary.LittleEndian.Uint32(stream[4:]))
+		if size == 0 {
+			assert.Len(t, stream, 8, "the end of stream marker is the last")
+			return metadata, headers, bodies
+		}
+		assert.Zero(t, size%8, "metadata is padded to 8 bytes")
+		r := fbReader{buf: stream[8 : 8+size]}
+		root := r.uint32(0)
+		assert.Zero(t, root%8, "tables are aligned")
+		assert.EqualValues(t, arrowMetadataV5, binary.LittleEndian.Uint16(r.buf[r.field(root, 0):]))
+		bodyLength := int(r.int64(r.field(root, 3)))
+		metadata = append(metadata, r)
+		headers = append(headers, r.object(root, 2))
+		bodies = append(bodies, stream[8+size:8+size+bodyLength])
+		stream = stream[8+size+bodyLength:]
+	}
+}
+
+func TestWriteArrow(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{state: "parse", end: start.Add(5 * time.Millisecond), duration: 5 * time.Millisecond, correlationID: "req-1"},
+		{state: "db", offset: 5 * time.Millisecond, end: start.Add(15 * time.Millisecond), duration: 10 * time.Millisecond,
+			data: map[string]interface{}{"rows": 2}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteArrow(&buf))
+	metadata, headers, bodies := readArrowMessages(t, buf.Bytes())
+	assert.Len(t, metadata, 2)
+
+	schema, fields := metadata[0], headers[0]
+	vector := schema.object(fields, 1)
+	var names []string
+	var kinds []byte
+	for i := 0; i < schema.uint32(vector); i++ {
+		pos := vector + 4 + 4*i
+		field := pos + schema.uint32(pos)
+		names = append(names, schema.string(schema.object(field, 0)))
+		kinds = append(kinds, schema.buf[schema.field(field, 2)])
+	}
+	assert.Equal(t, []string{"state", "started_at", "offset_ms", "duration_ms", "correlation_id", "run_id", "data"}, names)
+	assert.Equal(t, []byte{arrowTypeUtf8, arrowTypeTimestamp, arrowTypeFloat, arrowTypeFloat, arrowTypeUtf8, arrowTypeUtf8, arrowTypeUtf8}, kinds)
+
+	records, batch, body := metadata[1], headers[1], bodies[1]
+	assert.EqualValues(t, 2, records.int64(records.field(batch, 0)))
+	nodes := records.object(batch, 1)
+	assert.Equal(t, 7, records.uint32(nodes))
+	assert.Zero(t, (nodes+4)%8, "structs are aligned")
+
+	buffers := records.object(batch, 2)
+	assert.Equal(t, 7*2+4, records.uint32(buffers), "a validity buffer per column, offsets of utf8 columns")
+	buffer := func(i int) []byte {
+		pos := buffers + 4 + 16*i
+		offset := records.int64(pos)
+		assert.Zero(t, offset%8, "buffers are aligned")
+		return body[offset : offset+records.int64(pos+8)]
+	}
+
+	assert.Empty(t, buffer(0))
+	assert.Equal(t, []byte{0, 0, 0, 0, 5, 0, 0, 0, 7, 0, 0, 0}, buffer(1))
+	assert.Equal(t, "parsedb", string(buffer(2)))
+	assert.Equal(t, start.UnixNano()/1000, int64(binary.LittleEndian.Uint64(buffer(4))))
+	assert.Equal(t, 10.0, math.Float64frombits(binary.LittleEndian.Uint64(buffer(8)[8:])))
+	assert.Equal(t, "{\"rows\":2}", string(buffer(17)))
+}
+
+func TestWriteArrowEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, New(0, true).WriteArrow(&buf))
+	metadata, headers, _ := readArrowMessages(t, buf.Bytes())
+	assert.Len(t, metadata, 2)
+	assert.Zero(t, metadata[1].int64(metadata[1].field(headers[1], 0)))
+}
+
+func TestWriteArrowSummary(t *testing.T) {
+	clock := &fixedClock{now: time.Now()}
+	sw := NewWithClock(0, true, clock)
+	clock.now = clock.now.Add(10 * time.Millisecond)
+	sw.Lap("db")
+	clock.now = clock.now.Add(5 * time.Millisecond)
+	sw.Lap("parse")
+	clock.now = clock.now.Add(20 * time.Millisecond)
+	sw.Lap("db")
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteArrowSummary(&buf))
+	metadata, headers, bodies := readArrowMessages(t, buf.Bytes())
+
+	records, batch, body := metadata[1], headers[1], bodies[1]
+	assert.EqualValues(t, 2, records.int64(records.field(batch, 0)))
+	buffers := records.object(batch, 2)
+	buffer := func(i int) []byte {
+		pos := buffers + 4 + 16*i
+		offset := records.int64(pos)
+		return body[offset : offset+records.int64(pos+8)]
+	}
+	assert.Equal(t, "dbparse", string(buffer(2)))
+	assert.Equal(t, []byte{2, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, buffer(4))
+	assert.Equal(t, 15.0, math.Float64frombits(binary.LittleEndian.Uint64(buffer(8))))
+}
@@ -0,0 +1,208 @@
+package stopwatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QueuePolicy decides what AsyncSink does with a lap when its queue is full
+type QueuePolicy int
+
+const (
+	// QueueBlock makes the lap wait for room in the queue
+	QueueBlock QueuePolicy = iota
+	// QueueDropNewest drops the lap being recorded
+	QueueDropNewest
+	// QueueDropOldest drops the oldest queued lap to make room
+	QueueDropOldest
+)
+
+// AsyncOptions configures AsyncSink
+type AsyncOptions struct {
+	// QueueSize is the maximum number of pending laps, 1024 if zero
+	QueueSize int
+	// Policy is applied when the queue is full, QueueBlock by default
+	Policy QueuePolicy
+	// BatchSize flushes the sink after this many laps if it is a Flusher, like a buffered
+	// NDJSON sink. Zero flushes it on Flush or Close only.
+	BatchSize int
+	// FlushInterval flushes the sink periodically if it is a Flusher
+	FlushInterval time.Duration
+}
+
+// AsyncSink passes laps to another sink from a background goroutine,
+// so a slow exporter doesn't slow down the timed code.
+// The queue is bounded, see AsyncOptions. Close it to deliver the rest of laps.
+type AsyncSink struct {
+	sink Sink
+	opts AsyncOptions
+
+	mu      sync.Mutex
+	changed *sync.Cond
+	queue   []Lap
+	busy    bool // a lap is being written
+	closed  bool // no more laps are accepted
+	abandon bool // pending laps are not delivered
+	dropped int
+	batched int // laps written since the last flush
+	done    chan struct{}
+}
+
+// NewAsyncSink starts delivering laps to sink in the background
+func NewAsyncSink(sink Sink, opts AsyncOptions) *AsyncSink {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	a := &AsyncSink{sink: sink, opts: opts, done: make(chan struct{})}
+	a.changed = sync.NewCond(&a.mu)
+	go a.run()
+	if f, ok := sink.(Flusher); ok && opts.FlushInterval > 0 {
+		go flushEvery(opts.FlushInterval, a.done, f.Flush)
+	}
+	return a
+}
+
+// WriteLap queues the lap
+func (a *AsyncSink) WriteLap(lap Lap) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for !a.closed && len(a.queue) >= a.opts.QueueSize {
+		switch a.opts.Policy {
+		case QueueDropNewest:
+			a.dropped++
+			countLapsDropped(1)
+			return nil
+		case QueueDropOldest:
+			a.queue = a.queue[1:]
+			a.dropped++
+			countLapsDropped(1)
+		default:
+			a.changed.Wait()
+		}
+	}
+	if a.closed {
+		return ErrClosed
+	}
+
+	a.queue = append(a.queue, lap)
+	a.changed.Broadcast()
+	return nil
+}
+
+// Dropped returns the number of laps dropped because the queue was full,
+// or not delivered before the Close deadline
+func (a *AsyncSink) Dropped() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dropped
+}
+
+func (a *AsyncSink) run() {
+	defer close(a.done)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		for len(a.queue) == 0 && !a.closed {
+			a.changed.Wait()
+		}
+		if a.abandon || len(a.queue) == 0 {
+			return
+		}
+
+		lap := a.queue[0]
+		a.queue = a.queue[1:]
+		a.busy = true
+		a.changed.Broadcast()
+		a.mu.Unlock()
+
+		_ = countExport(a.sink.WriteLap(lap))
+		a.flushBatch()
+
+		a.mu.Lock()
+		a.busy = false
+		a.changed.Broadcast()
+	}
+}
+
+// flushBatch flushes the sink when a batch is written, called by the worker only
+func (a *AsyncSink) flushBatch() {
+	f, ok := a.sink.(Flusher)
+	if !ok || a.opts.BatchSize <= 0 {
+		return
+	}
+	a.batched++
+	if a.batched >= a.opts.BatchSize {
+		a.batched = 0
+		_ = f.Flush(context.Background())
+	}
+}
+
+// ForceFlush delivers queued laps and flushes the sink right away,
+// see Flush to limit the time it takes
+func (a *AsyncSink) ForceFlush() error {
+	return a.Flush(context.Background())
+}
+
+// Flush waits until all queued laps are delivered, and flushes the sink if it is a Flusher
+func (a *AsyncSink) Flush(ctx context.Context) error {
+	drained := make(chan struct{})
+	gaveUp := false // guarded by a.mu, stops the waiter when ctx is done
+	go func() {
+		a.mu.Lock()
+		for (len(a.queue) > 0 || a.busy) && !gaveUp {
+			a.changed.Wait()
+		}
+		a.mu.Unlock()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		a.mu.Lock()
+		gaveUp = true
+		a.changed.Broadcast()
+		a.mu.Unlock()
+		<-drained
+		return ctx.Err()
+	}
+
+	if f, ok := a.sink.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// Close stops accepting laps and delivers queued laps until ctx is done.
+// Laps still queued then are counted as dropped. The sink is closed if it is a SinkCloser,
+// even when ctx is done, so its resources are released. The first error is returned.
+func (a *AsyncSink) Close(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	a.changed.Broadcast()
+	a.mu.Unlock()
+
+	var err error
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		a.mu.Lock()
+		a.abandon = true
+		a.dropped += len(a.queue)
+		countLapsDropped(len(a.queue))
+		a.queue = nil
+		a.changed.Broadcast()
+		a.mu.Unlock()
+		err = ctx.Err()
+	}
+
+	if c, ok := a.sink.(SinkCloser); ok {
+		if closeErr := c.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,205 @@
+package stopwatch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedSink records laps, each write waits for the gate
+type gatedSink struct {
+	gate chan struct{}
+
+	mu     sync.Mutex
+	states []string
+	closed bool
+}
+
+func (g *gatedSink) WriteLap(lap Lap) error {
+	<-g.gate
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.states = append(g.states, lap.state)
+	return nil
+}
+
+func (g *gatedSink) Close(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+	return nil
+}
+
+func (g *gatedSink) isClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed
+}
+
+func (g *gatedSink) written() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.states...)
+}
+
+// fillQueue writes the first lap and waits until the worker takes it,
+// so the following laps stay in the queue
+func fillQueue(t *testing.T, a *AsyncSink, states ...string) {
+	assert.NoError(t, a.WriteLap(Lap{state: states[0]}))
+	assert.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.busy
+	}, time.Second, time.Millisecond)
+	for _, state := range states[1:] {
+		assert.NoError(t, a.WriteLap(Lap{state: state}))
+	}
+}
+
+func TestAsyncSinkDelivers(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	close(sink.gate)
+	a := NewAsyncSink(sink, AsyncOptions{})
+
+	sw := New(0, true)
+	sw.AddSink(a)
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+
+	assert.NoError(t, a.Flush(context.Background()))
+	assert.Equal(t, []string{"lap1", "lap2"}, sink.written())
+
+	assert.NoError(t, sw.Close(context.Background()))
+	assert.Equal(t, ErrClosed, a.WriteLap(Lap{state: "lap3"}))
+	assert.Zero(t, a.Dropped())
+}
+
+func TestAsyncSinkDropNewest(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	a := NewAsyncSink(sink, AsyncOptions{QueueSize: 2, Policy: QueueDropNewest})
+
+	fillQueue(t, a, "lap1", "lap2", "lap3", "lap4")
+	close(sink.gate)
+
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, []string{"lap1", "lap2", "lap3"}, sink.written())
+	assert.Equal(t, 1, a.Dropped())
+}
+
+func TestAsyncSinkDropOldest(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	a := NewAsyncSink(sink, AsyncOptions{QueueSize: 2, Policy: QueueDropOldest})
+
+	fillQueue(t, a, "lap1", "lap2", "lap3", "lap4")
+	close(sink.gate)
+
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, []string{"lap1", "lap3", "lap4"}, sink.written())
+	assert.Equal(t, 1, a.Dropped())
+}
+
+func TestAsyncSinkBlock(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	a := NewAsyncSink(sink, AsyncOptions{QueueSize: 1})
+
+	fillQueue(t, a, "lap1", "lap2")
+	written := make(chan struct{})
+	go func() {
+		assert.NoError(t, a.WriteLap(Lap{state: "lap3"}))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("must wait for room in the queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(sink.gate)
+	<-written
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, []string{"lap1", "lap2", "lap3"}, sink.written())
+	assert.Zero(t, a.Dropped())
+}
+
+func TestAsyncSinkCloseDeadline(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	a := NewAsyncSink(sink, AsyncOptions{})
+	fillQueue(t, a, "lap1", "lap2", "lap3")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, a.Close(ctx))
+	assert.Equal(t, 2, a.Dropped())
+	assert.True(t, sink.isClosed(), "the sink is closed even after the deadline")
+
+	close(sink.gate)
+	assert.NoError(t, a.Flush(context.Background()))
+	assert.Equal(t, []string{"lap1"}, sink.written())
+}
+
+func TestAsyncSinkFlushDeadline(t *testing.T) {
+	sink := &gatedSink{gate: make(chan struct{})}
+	a := NewAsyncSink(sink, AsyncOptions{})
+	fillQueue(t, a, "lap1", "lap2")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	before := runtime.NumGoroutine()
+	assert.Equal(t, context.DeadlineExceeded, a.Flush(ctx))
+	assert.Equal(t, before, runtime.NumGoroutine(), "the waiter is gone")
+
+	close(sink.gate)
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, []string{"lap1", "lap2"}, sink.written())
+}
+
+func TestAsyncSinkBatchSize(t *testing.T) {
+	var buf syncBuffer
+	w := bufio.NewWriter(&buf)
+	a := NewAsyncSink(NewNDJSONSink(w), AsyncOptions{BatchSize: 2})
+
+	assert.NoError(t, a.WriteLap(Lap{state: "lap1"}))
+	assert.NoError(t, a.WriteLap(Lap{state: "lap2"}))
+	assert.Eventually(t, func() bool { return strings.Count(buf.String(), "\n") == 2 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, a.WriteLap(Lap{state: "lap3"}))
+	assert.NoError(t, a.ForceFlush())
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+	assert.NoError(t, a.Close(context.Background()))
+}
+
+func TestAsyncSinkFlushInterval(t *testing.T) {
+	var buf syncBuffer
+	w := bufio.NewWriter(&buf)
+	a := NewAsyncSink(NewNDJSONSink(w), AsyncOptions{FlushInterval: time.Millisecond})
+
+	assert.NoError(t, a.WriteLap(Lap{state: "lap1"}))
+	assert.Eventually(t, func() bool { return strings.Count(buf.String(), "\n") == 1 }, time.Second, time.Millisecond)
+	assert.NoError(t, a.Close(context.Background()))
+}
+
+// syncBuffer is a bytes.Buffer safe to read while written
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,169 @@
+package stopwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Budget limits the whole run and laps by their state
+type Budget struct {
+	// Total is the budget of the whole run, zero means no limit
+	Total time.Duration
+	// Laps are budgets of single laps by lap state
+	Laps map[string]time.Duration
+}
+
+// Alert describes an exceeded budget
+type Alert struct {
+	// State is the state of the lap over its budget, empty if the total budget is exceeded
+	State string `json:"state,omitempty"`
+	// BudgetMs and ActualMs are the budget and the time taken, in milliseconds
+	BudgetMs float64 `json:"budget_ms"`
+	ActualMs float64 `json:"actual_ms"`
+	// Snapshot is the stopwatch in FormattingModeJsonFull at the moment of the alert
+	Snapshot json.RawMessage `json:"snapshot"`
+}
+
+// budgetWatch is a sink checking laps, with a timer checking the total budget
+type budgetWatch struct {
+	sw *Stopwatch
+
+	mu         sync.Mutex
+	budget     Budget
+	alert      func(Alert)
+	timer      *time.Timer
+	totalFired bool
+}
+
+// SetBudget fires alert when a lap exceeds its budget, or when the whole run exceeds
+// the total budget. The total budget is watched by a timer, so the alert fires right when
+// the budget is exceeded, even if the run takes hours more. It fires once,
+// call SetBudget again to rearm it, e.g. after Reset. See WebhookAlert.
+// A stopwatch WithoutLocking has no timer, its total budget is checked by laps only.
+func (s *Stopwatch) SetBudget(budget Budget, alert func(Alert)) {
+	s.lock()
+	watch := s.budgetWatch
+	if watch == nil {
+		watch = &budgetWatch{sw: s}
+		s.budgetWatch = watch
+		s.sinks = append(s.sinks, watch)
+	}
+	timed := !s.noLocking
+	s.unlock()
+
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	watch.budget = budget
+	watch.alert = alert
+	watch.totalFired = false
+	watch.stopTimerLocked()
+	if budget.Total > 0 && alert != nil && timed {
+		watch.scheduleLocked()
+	}
+}
+
+// scheduleLocked arms the single timer of the watch, it must be called under watch.mu
+func (w *budgetWatch) scheduleLocked() {
+	remaining := w.budget.Total - w.sw.elapsedSnapshot()
+	if remaining < 0 {
+		remaining = 0
+	}
+	if w.timer != nil {
+		w.timer.Reset(remaining)
+		return
+	}
+	w.timer = time.AfterFunc(remaining, func() { w.checkTotal(true) })
+}
+
+// checkTotal fires the alert if the total budget is exceeded. The timer reschedules itself
+// if the stopwatch was paused in the meantime, laps only check.
+func (w *budgetWatch) checkTotal(reschedule bool) {
+	w.mu.Lock()
+	if w.totalFired || w.budget.Total <= 0 || w.alert == nil {
+		w.mu.Unlock()
+		return
+	}
+	elapsed := w.sw.elapsedSnapshot()
+	if elapsed < w.budget.Total {
+		if reschedule {
+			w.scheduleLocked()
+		}
+		w.mu.Unlock()
+		return
+	}
+	w.totalFired = true
+	budget, alert := w.budget.Total, w.alert
+	w.mu.Unlock()
+
+	alert(w.newAlert("", budget, elapsed))
+}
+
+func (w *budgetWatch) WriteLap(lap Lap) error {
+	w.mu.Lock()
+	budget, found := w.budget.Laps[lap.state]
+	alert := w.alert
+	w.mu.Unlock()
+
+	if found && alert != nil && lap.duration > budget {
+		alert(w.newAlert(lap.state, budget, lap.duration))
+	}
+	w.checkTotal(false)
+	return nil
+}
+
+// Close stops watching the total budget
+func (w *budgetWatch) Close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopTimerLocked()
+	w.alert = nil
+	return nil
+}
+
+// stopTimerLocked must be called under w.mu
+func (w *budgetWatch) stopTimerLocked() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+func (w *budgetWatch) newAlert(state string, budget, actual time.Duration) Alert {
+	snapshot, _ := w.sw.formatAs(FormattingModeJsonFull)
+	return Alert{
+		State:    state,
+		BudgetMs: milliseconds(budget),
+		ActualMs: milliseconds(actual),
+		Snapshot: json.RawMessage(snapshot),
+	}
+}
+
+// DefaultWebhookTimeout limits requests of WebhookAlert without a client
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookAlert returns an alert function posting the alert as JSON to the url.
+// The alert is posted from a goroutine and delivery errors are ignored, so a slow
+// or broken webhook can't block or break the timed code. A nil client is one
+// with DefaultWebhookTimeout.
+func WebhookAlert(client *http.Client, url string) func(Alert) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return func(alert Alert) {
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return
+		}
+		go func() {
+			resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+}
@@ -0,0 +1,135 @@
+package stopwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *alertRecorder) record(alert Alert) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+}
+
+func (r *alertRecorder) get() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+func TestLapBudget(t *testing.T) {
+	recorder := &alertRecorder{}
+	sw := New(0, true)
+	sw.SetBudget(Budget{Laps: map[string]time.Duration{"db": time.Millisecond}}, recorder.record)
+
+	now := time.Now()
+	sw.LapWithDataAndTime(now, "parse", nil)
+	sw.LapWithDataAndTime(now.Add(500*time.Microsecond), "db", nil)
+	assert.Empty(t, recorder.get())
+
+	sw.LapWithDataAndTime(now.Add(2*time.Millisecond), "db", nil)
+	alerts := recorder.get()
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "db", alerts[0].State)
+	assert.Equal(t, 1.0, alerts[0].BudgetMs)
+	assert.Equal(t, 1.5, alerts[0].ActualMs)
+
+	var snapshot FullStopwatch
+	assert.NoError(t, json.Unmarshal(alerts[0].Snapshot, &snapshot))
+	assert.Len(t, snapshot.Laps, 3)
+}
+
+func TestTotalBudgetTimer(t *testing.T) {
+	recorder := &alertRecorder{}
+	sw := New(0, true)
+	sw.SetBudget(Budget{Total: 5 * time.Millisecond}, recorder.record)
+
+	assert.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, time.Millisecond)
+	alert := recorder.get()[0]
+	assert.Empty(t, alert.State)
+	assert.True(t, alert.ActualMs >= 5)
+
+	sw.Lap("late")
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, recorder.get(), 1, "total budget alert fires once")
+}
+
+func TestTotalBudgetWaitsForPausedStopwatch(t *testing.T) {
+	recorder := &alertRecorder{}
+	sw := New(0, false)
+	sw.SetBudget(Budget{Total: time.Millisecond}, recorder.record)
+
+	time.Sleep(5 * time.Millisecond)
+	assert.Empty(t, recorder.get())
+	sw.SetBudget(Budget{}, nil)
+}
+
+func TestWebhookAlert(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+	}))
+	defer server.Close()
+
+	sw := New(0, true)
+	sw.SetBudget(Budget{Laps: map[string]time.Duration{"db": 0}}, WebhookAlert(nil, server.URL))
+	sw.LapWithDataAndTime(time.Now().Add(time.Millisecond), "db", nil)
+
+	alert := <-received
+	assert.Equal(t, "db", alert.State)
+	assert.NotEmpty(t, alert.Snapshot)
+}
+
+func TestTotalBudgetSingleTimer(t *testing.T) {
+	sw := New(0, true)
+	sw.SetBudget(Budget{Total: time.Hour}, func(Alert) {})
+	watch := sw.budgetWatch
+	timer := watch.timer
+
+	for i := 0; i < 100; i++ {
+		sw.Lap("db")
+	}
+	watch.mu.Lock()
+	assert.Same(t, timer, watch.timer, "laps must not arm new timers")
+	watch.mu.Unlock()
+	assert.NoError(t, sw.Close(context.Background()))
+}
+
+func TestWebhookAlertDoesNotBlock(t *testing.T) {
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(arrived)
+		<-release
+	}))
+	defer server.Close()
+
+	done := make(chan struct{})
+	go func() {
+		WebhookAlert(nil, server.URL)(Alert{State: "db"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the alert blocked on the webhook")
+	}
+
+	// the request must not outlive the server, its port may be reused by other tests
+	<-arrived
+	close(release)
+}
@@ -0,0 +1,115 @@
+package stopwatch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChildrenKey is the key of child stopwatches in formatting modes producing a JSON object
+const ChildrenKey = "children"
+
+type child struct {
+	name string
+	sw   *Stopwatch
+}
+
+// Child creates a running stopwatch of a sub-operation, e.g. DB calls within a request,
+// attached to the stopwatch under the name. It takes the formatter, the formatting mode
+// and other formatting settings of the stopwatch, its clock, its run ID and correlation ID,
+// its locking, and the sanitizing, redaction, truncation and allowed states of its lap data,
+// so laps of the child are formatted and cleaned like laps of the stopwatch.
+// Formatting the stopwatch includes its children, so a single String call gives
+// the whole breakdown of an operation: modes producing arrays or lines of laps include
+// laps of children with states prefixed by their names, like "db.query", modes producing
+// an object get children in ChildrenKey formatted in the same mode, by name.
+// FormattingModeFlat gets columns of children instead, like "child_db_query_ms", and
+// FormattingModeJsonSummary gets their states prefixed. FormattingModeTemplate leaves children out. Reset detaches children.
+func (s *Stopwatch) Child(name string) *Stopwatch {
+	sw := NewWithClock(0, true, s.clock)
+
+	s.rlock()
+	sw.formatter = s.formatter
+	sw.formattingMode = s.formattingMode
+	sw.keyNaming = s.keyNaming
+	sw.duplicateKeys = s.duplicateKeys
+	sw.precision = s.precision
+	sw.integerUnit = s.integerUnit
+	sw.resolution = s.resolution
+	sw.schema = s.schema
+	sw.runID = s.runID
+	sw.correlationID = s.correlationID
+	sw.noLocking = s.noLocking
+	sw.sanitizer = s.sanitizer
+	sw.redacted = copySet(s.redacted)
+	sw.maxState = s.maxState
+	sw.maxValue = s.maxValue
+	sw.allowedStates = copySet(s.allowedStates)
+	s.runlock()
+
+	s.lock()
+	s.children = append(s.children, child{name: name, sw: sw})
+	s.unlock()
+	return sw
+}
+
+// copySet copies a set, so the child adding to it doesn't change the parent
+func copySet(set map[string]struct{}) map[string]struct{} {
+	if set == nil {
+		return nil
+	}
+	copied := make(map[string]struct{}, len(set))
+	for k := range set {
+		copied[k] = struct{}{}
+	}
+	return copied
+}
+
+// treeLaps returns laps of the stopwatch together with laps of its children, their states
+// prefixed by child names and their offsets counted from the start of the stopwatch,
+// in order of recording. It must be called under the read lock.
+func (s *Stopwatch) treeLaps() []Lap {
+	if len(s.children) == 0 {
+		return s.laps
+	}
+
+	laps := append([]Lap(nil), s.laps...)
+	for _, child := range s.children {
+		child.sw.rlock()
+		offset := child.sw.start.Sub(s.start)
+		for _, lap := range child.sw.treeLaps() {
+			lap.state = child.name + "." + lap.state
+			lap.offset += offset
+			laps = append(laps, lap)
+		}
+		child.sw.runlock()
+	}
+	sort.SliceStable(laps, func(i, j int) bool { return laps[i].end.Before(laps[j].end) })
+	return laps
+}
+
+// addChildren adds children formatted in the mode to an output being a JSON object.
+// It must be called under the read lock.
+func (s *Stopwatch) addChildren(output string, mode FormattingMode) (string, error) {
+	if mode == FormattingModeTemplate || mode == FormattingModeFlat || !strings.HasSuffix(output, "}") {
+		return output, nil
+	}
+
+	fields := make([]string, len(s.children))
+	for i, child := range s.children {
+		formatted, err := child.sw.formatAs(mode)
+		if err != nil {
+			return "", fmt.Errorf("child %q: %w", child.name, err)
+		}
+		fields[i] = jsonString(child.name) + ":" + formatted
+	}
+
+	separator := "," // of encoding/json
+	switch {
+	case strings.HasSuffix(output, "{}"):
+		separator = ""
+	case mode == FormattingModeJsonSimpleObject || mode == FormattingModeJsonMsObject || mode == FormattingModeJsonIntObject:
+		separator = ", "
+	}
+	return fmt.Sprintf(`%s%s"%s":{%s}}`, strings.TrimSuffix(output, "}"), separator, ChildrenKey, strings.Join(fields, separator)), nil
+}
@@ -0,0 +1,101 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChild(t *testing.T) {
+	clock := &fixedClock{now: time.Unix(0, 0)}
+	request := NewWithClock(0, true, clock)
+	request.SetFormattingMode(FormattingModeJsonIntObject)
+	request.SetIntegerUnit(time.Millisecond)
+
+	clock.now = clock.now.Add(time.Millisecond)
+	request.Lap("parse")
+	db := request.Child("db")
+	clock.now = clock.now.Add(2 * time.Millisecond)
+	db.Lap("query")
+	cache := db.Child("cache")
+	clock.now = clock.now.Add(3 * time.Millisecond)
+	cache.Lap("get")
+	request.Lap("render")
+
+	assert.Equal(t, `{"parse":1, "render":5, "children":{"db":{"query":2, "children":{"cache":{"get":3}}}}}`, request.String())
+
+	request.SetFormattingMode(FormattingModeJsonArray)
+	assert.Equal(t,
+		`[{"state":"parse", "time":"1ms"}, {"state":"db.query", "time":"2ms"}, {"state":"render", "time":"5ms"}, {"state":"db.cache.get", "time":"3ms"}]`,
+		request.String())
+
+	request.SetFormattingMode(FormattingModeJsonDetailed)
+	var laps []DetailedLap
+	assert.NoError(t, json.Unmarshal([]byte(request.String()), &laps))
+	if assert.Len(t, laps, 4) {
+		assert.Equal(t, "db.cache.get", laps[3].State)
+		assert.Equal(t, 3.0, laps[3].OffsetMs)
+	}
+
+	request.SetFormattingMode(FormattingModeJsonFull)
+	var full struct {
+		ElapsedMs float64 `json:"elapsed_ms"`
+		Children  map[string]struct {
+			ElapsedMs float64 `json:"elapsed_ms"`
+		} `json:"children"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(request.String()), &full))
+	assert.Equal(t, 6.0, full.ElapsedMs)
+	assert.Equal(t, 5.0, full.Children["db"].ElapsedMs)
+
+	request.SetFormattingMode(FormattingModeFlat)
+	assert.Contains(t, request.String(),
+		`"parse_ms":1,"render_ms":5,"child_db_total_ms":5,"child_db_query_ms":2,"child_db_child_cache_total_ms":3,"child_db_child_cache_get_ms":3}`)
+
+	request.SetFormattingMode(FormattingModeJsonSummary)
+	var summary []struct {
+		State   string  `json:"state"`
+		TotalMs float64 `json:"total_ms"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(request.String()), &summary))
+	if assert.Len(t, summary, 4) {
+		assert.Equal(t, "db.cache.get", summary[0].State)
+		assert.Equal(t, 3.0, summary[0].TotalMs)
+		assert.Equal(t, "db.query", summary[1].State)
+		assert.Equal(t, "render", summary[3].State)
+	}
+
+	request.Reset(0, true)
+	request.SetFormattingMode(FormattingModeJsonIntObject)
+	assert.Equal(t, `{}`, request.String())
+}
+
+func TestChildOfEmpty(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeJsonSimpleObject)
+	sw.Child(`say "hi"`)
+	assert.Equal(t, `{"children":{"say \"hi\"":{}}}`, sw.String())
+}
+
+func TestChildLapData(t *testing.T) {
+	sw := New(0, true)
+	sw.RedactKeys("password")
+	sw.SetTruncation(0, 10)
+	sw.SetAllowedStates("query")
+	sw.SetCorrelationID("req-1")
+	db := sw.Child("db")
+	db.RedactKeys("token")
+	db.LapWithData("q", map[string]interface{}{"password": "hunter2", "sql": "SELECT * FROM users"})
+
+	laps := db.Laps()
+	if assert.Len(t, laps, 1) {
+		assert.Equal(t, RedactedValue, laps[0].data["password"])
+		assert.Equal(t, "SELECT * F"+TruncationMarker, laps[0].data["sql"])
+		assert.Equal(t, true, laps[0].data[UnexpectedStateKey])
+		assert.Equal(t, "req-1", laps[0].correlationID)
+	}
+	assert.NotContains(t, sw.String(), "hunter2")
+	assert.NotContains(t, sw.redacted, "token")
+}
@@ -0,0 +1,84 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	// DefaultChunkSize is the chunk size of LapsHandler unless a request sets it
+	DefaultChunkSize = 1000
+	// MaxChunkSize is the largest chunk size a request to LapsHandler may set
+	MaxChunkSize = 100000
+)
+
+// LapChunk is a part of stopwatch laps returned by Chunk
+type LapChunk struct {
+	Laps []DetailedLap `json:"laps"`
+	// Next is the token of the next chunk, empty if there are no more laps yet
+	Next string `json:"next,omitempty"`
+}
+
+// Chunk returns up to size laps recorded after the lap of the token, starting from the first lap
+// if the token is empty. Tokens are lap sequence numbers, see Lap.Seq, so they stay valid
+// while new laps are recorded and old ones evicted. It lets a stopwatch with millions of laps
+// be exported piece by piece.
+func (s *Stopwatch) Chunk(token string, size int) (LapChunk, error) {
+	if size <= 0 {
+		return LapChunk{}, fmt.Errorf("invalid chunk size %d", size)
+	}
+	var after uint64
+	if token != "" {
+		var err error
+		if after, err = strconv.ParseUint(token, 10, 64); err != nil {
+			return LapChunk{}, fmt.Errorf("invalid chunk token %q", token)
+		}
+	}
+
+	s.rlock()
+	defer s.runlock()
+	// laps are kept in the order of their sequence numbers
+	first := sort.Search(len(s.laps), func(i int) bool { return s.laps[i].seq > after })
+	if size > len(s.laps)-first {
+		size = len(s.laps) - first
+	}
+	last := first + size
+
+	chunk := LapChunk{Laps: make([]DetailedLap, 0, last-first)}
+	for _, lap := range s.laps[first:last] {
+		chunk.Laps = append(chunk.Laps, newDetailedLap(lap))
+	}
+	if last < len(s.laps) {
+		chunk.Next = strconv.FormatUint(s.laps[last-1].seq, 10)
+	}
+	return chunk, nil
+}
+
+// LapsHandler serves laps of the stopwatch in chunks as JSON, see Chunk.
+// The "after" query parameter is the token, "size" is the chunk size, DefaultChunkSize by default
+// and MaxChunkSize at most.
+//
+//	http.Handle("/debug/stopwatch/laps", sw.LapsHandler())
+func (s *Stopwatch) LapsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := DefaultChunkSize
+		if value := r.URL.Query().Get("size"); value != "" {
+			var err error
+			if size, err = strconv.Atoi(value); err != nil || size > MaxChunkSize {
+				http.Error(w, fmt.Sprintf("invalid chunk size %q", value), http.StatusBadRequest)
+				return
+			}
+		}
+
+		chunk, err := s.Chunk(r.URL.Query().Get("after"), size)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(chunk)
+	})
+}
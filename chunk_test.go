@@ -0,0 +1,70 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunk(t *testing.T) {
+	sw := New(0, true)
+	sw.SetMaxLaps(5)
+	for i := 0; i < 7; i++ {
+		sw.Lap("lap")
+	}
+
+	var seqs []uint64
+	token := ""
+	for {
+		chunk, err := sw.Chunk(token, 2)
+		assert.NoError(t, err)
+		for _, lap := range chunk.Laps {
+			seqs = append(seqs, lap.Seq)
+		}
+		if chunk.Next == "" {
+			break
+		}
+		token = chunk.Next
+		sw.Lap("lap") // new laps don't break tokens
+	}
+	assert.Equal(t, []uint64{3, 4, 5, 6, 7, 8, 9, 10}, seqs)
+
+	_, err := sw.Chunk("abc", 2)
+	assert.Error(t, err)
+	_, err = sw.Chunk("", 0)
+	assert.Error(t, err)
+
+	chunk, err := sw.Chunk("2", math.MaxInt64)
+	assert.NoError(t, err)
+	assert.Len(t, chunk.Laps, 5)
+	assert.Empty(t, chunk.Next)
+}
+
+func TestLapsHandler(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+	sw.Lap("lap3")
+
+	rec := httptest.NewRecorder()
+	sw.LapsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/laps?after=1&size=1", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var chunk LapChunk
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &chunk))
+	if assert.Len(t, chunk.Laps, 1) {
+		assert.Equal(t, "lap2", chunk.Laps[0].State)
+	}
+	assert.Equal(t, "2", chunk.Next)
+
+	rec = httptest.NewRecorder()
+	sw.LapsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/laps?size=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	sw.LapsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/laps?size=9223372036854775807", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
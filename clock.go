@@ -0,0 +1,16 @@
+package stopwatch
+
+import "time"
+
+// Clock is a source of time, see NewWithClock
+type Clock interface {
+	Now() time.Time
+}
+
+// now reads the clock of the stopwatch, it never changes after New, so no lock is needed
+func (s *Stopwatch) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
@@ -0,0 +1,37 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestNewWithClock(t *testing.T) {
+	clock := &fixedClock{now: time.Unix(100, 0)}
+	sw := NewWithClock(0, true, clock)
+	assert.Equal(t, time.Unix(100, 0), sw.start)
+
+	clock.now = clock.now.Add(time.Second)
+	sw.Lap("db")
+	clock.now = clock.now.Add(2 * time.Second)
+	sw.Lapf("cache %d", 1)
+	clock.now = clock.now.Add(3 * time.Second)
+	sw.Stop()
+
+	laps := sw.Laps()
+	if assert.Len(t, laps, 2) {
+		assert.Equal(t, time.Second, laps[0].Duration())
+		assert.Equal(t, 2*time.Second, laps[1].Duration())
+	}
+	assert.Equal(t, 6*time.Second, sw.ElapsedTime())
+	assert.Equal(t, time.Unix(106, 0), sw.stop)
+}
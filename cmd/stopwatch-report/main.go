@@ -0,0 +1,98 @@
+// Command stopwatch-report renders stopwatch JSON dumps as tables or Gantt charts.
+//
+// Usage:
+//
+//	stopwatch-report [-format table|gantt] [-sort] [-cut 95] [-adaptive] [-color] [-unit 1us] [file...]
+//	stopwatch-report -merge [-unit 1us] file...
+//
+// Without files, a single dump is read from stdin. With -merge, dumps of many processes
+// are aggregated into a single table of per-state statistics. Lap times written as plain
+// numbers are milliseconds, -unit sets another unit, e.g. 1us for JSON_OBJECT_INT dumps.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/alexus1024/stopwatch/report"
+)
+
+func main() {
+	format := flag.String("format", string(report.FormatTable), "output format: table or gantt")
+	byDuration := flag.Bool("sort", false, "table: sort laps from the longest to the shortest")
+	cutAt := flag.Float64("cut", 0, "table: stop listing laps after this percentage of time is covered")
+	adaptive := flag.Bool("adaptive", false, "table: pick the unit per lap and align values")
+	color := flag.Bool("color", false, "table: color laps by their severity")
+	merge := flag.Bool("merge", false, "aggregate all files into a single report")
+	unit := flag.Duration("unit", time.Millisecond, "unit of lap times written as plain numbers, e.g. 1us for JSON_OBJECT_INT")
+	flag.Parse()
+
+	if *merge {
+		exitOnError(mergeFiles(flag.Args(), *unit, os.Stdout))
+		return
+	}
+
+	render := func(r io.Reader, w io.Writer) error {
+		laps, err := report.ParseUnit(r, *unit)
+		if err != nil {
+			return err
+		}
+		if report.Format(*format) != report.FormatTable {
+			return report.RenderLaps(laps, w, report.Format(*format))
+		}
+		return report.RenderTable(laps, w, report.TableOptions{ByDuration: *byDuration, CutAt: *cutAt, Adaptive: *adaptive, Color: *color})
+	}
+
+	if flag.NArg() == 0 {
+		exitOnError(render(os.Stdin, os.Stdout))
+		return
+	}
+
+	for i, name := range flag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", name)
+		exitOnError(renderFile(name, os.Stdout, render))
+	}
+}
+
+func renderFile(name string, w io.Writer, render func(io.Reader, io.Writer) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return render(f, w)
+}
+
+func mergeFiles(names []string, unit time.Duration, w io.Writer) error {
+	dumps := make([][]report.Lap, len(names))
+	for i, name := range names {
+		laps, err := parseFile(name, unit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		dumps[i] = laps
+	}
+	return report.RenderMerged(report.MergeLaps(dumps...), w, names...)
+}
+
+func parseFile(name string, unit time.Duration) ([]report.Lap, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return report.ParseUnit(f, unit)
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "stopwatch-report:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,124 @@
+// Command stopwatch times an external command, or lap markers read from stdin,
+// and prints the stopwatch report in any supported formatting mode.
+//
+// Usage:
+//
+//	stopwatch [-mode JSON_OBJECT_MS] [-precision 3] -- command [args...]
+//	./build.sh | stopwatch -stdin
+//	stopwatch -template '{{range .Laps}}{{.State}} {{end}}' -- command [args...]
+//
+// In the command mode the report is written to stderr, so the command's output stays intact.
+// In the stdin mode every non-empty line finishes a lap named by that line.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/alexus1024/stopwatch"
+)
+
+// modes are the formatting modes accepted by -mode, TEMPLATE takes a template set by -template
+var modes = []stopwatch.FormattingMode{
+	stopwatch.FormattingModeJsonArray,
+	stopwatch.FormattingModeJsonSimpleObject,
+	stopwatch.FormattingModeJsonMsObject,
+	stopwatch.FormattingModeJsonIntObject,
+	stopwatch.FormattingModeJsonDetailed,
+	stopwatch.FormattingModeNDJSON,
+	stopwatch.FormattingModeJsonFull,
+	stopwatch.FormattingModeECS,
+	stopwatch.FormattingModeGoogleCloud,
+	stopwatch.FormattingModeCloudWatchEMF,
+	stopwatch.FormattingModeFlat,
+	stopwatch.FormattingModeTemplate,
+	stopwatch.FormattingModeJsonSummary,
+}
+
+// modeNames lists modes for the -mode help
+func modeNames() string {
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ", ")
+}
+
+func main() {
+	mode := flag.String("mode", string(stopwatch.FormattingModeJsonArray), "formatting mode: "+modeNames())
+	precision := flag.Int("precision", 3, "decimal places of milliseconds in JSON_OBJECT_MS mode")
+	template := flag.String("template", "", "text/template of the report, sets the TEMPLATE mode")
+	stdin := flag.Bool("stdin", false, "read lap markers from stdin instead of running a command")
+	flag.Parse()
+
+	sw := stopwatch.New(0, true)
+	sw.SetFormattingMode(stopwatch.FormattingMode(*mode))
+	sw.SetPrecision(*precision)
+	if *template != "" {
+		if err := sw.SetTemplate(*template); err != nil {
+			fmt.Fprintln(os.Stderr, "stopwatch:", err)
+			os.Exit(2)
+		}
+	}
+
+	if *stdin {
+		if err := lapLines(sw, os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, "stopwatch:", err)
+			os.Exit(1)
+		}
+		sw.Stop()
+		fmt.Println(sw.String())
+		return
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	exitCode := run(sw, flag.Args())
+	sw.Stop()
+	fmt.Fprintln(os.Stderr, sw.String())
+	os.Exit(exitCode)
+}
+
+// lapLines records a lap for every non-empty line
+func lapLines(sw *stopwatch.Stopwatch, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if state := strings.TrimSpace(scanner.Text()); state != "" {
+			sw.Lap(state)
+		}
+	}
+	return scanner.Err()
+}
+
+// run executes the command and records it as a single lap with its exit code
+func run(sw *stopwatch.Stopwatch, args []string) int {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		} else {
+			fmt.Fprintln(os.Stderr, "stopwatch:", err)
+			exitCode = 127
+		}
+	}
+
+	sw.LapWithData(strings.Join(args, " "), map[string]interface{}{
+		"exit_code": exitCode,
+	})
+	return exitCode
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLapLines(t *testing.T) {
+	sw := stopwatch.New(0, true)
+
+	err := lapLines(sw, strings.NewReader("compile\n\n  test  \npackage\n"))
+	assert.NoError(t, err)
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 3)
+	assert.Equal(t, "compile", laps[0].State())
+	assert.Equal(t, "test", laps[1].State())
+	assert.Equal(t, "package", laps[2].State())
+}
+
+func TestModes(t *testing.T) {
+	assert.Contains(t, modeNames(), "JSON_SUMMARY")
+
+	for _, mode := range modes {
+		sw := stopwatch.New(0, true)
+		sw.SetFormattingMode(mode)
+		if mode == stopwatch.FormattingModeTemplate {
+			assert.NoError(t, sw.SetTemplate(`{{range .Laps}}{{.State}}{{end}}`))
+		}
+		sw.Lap("compile")
+		assert.NotContains(t, sw.String(), `"error"`, mode)
+	}
+}
@@ -0,0 +1,50 @@
+package stopwatch
+
+import (
+	"time"
+)
+
+// LapColumns holds laps column by column. Numeric libraries like gonum take the float64
+// columns as they are, and Arrow record batches are built from them without intermediate JSON:
+//
+//	cols := sw.Columns()
+//	durations := array.NewFloat64Builder(memory.DefaultAllocator)
+//	durations.AppendValues(cols.DurationsMs, nil)
+//
+// To hand laps to another process or library as Arrow IPC, see WriteArrow.
+type LapColumns struct {
+	States         []string
+	StartedAt      []time.Time
+	OffsetsMs      []float64
+	DurationsMs    []float64
+	CorrelationIDs []string
+	// Data has lap data maps, nil for laps without data
+	Data []map[string]interface{}
+}
+
+// Len returns the number of laps
+func (c LapColumns) Len() int {
+	return len(c.States)
+}
+
+// Columns returns laps column by column, see LapColumns
+func (s *Stopwatch) Columns() LapColumns {
+	laps := s.Laps()
+	cols := LapColumns{
+		States:         make([]string, len(laps)),
+		StartedAt:      make([]time.Time, len(laps)),
+		OffsetsMs:      make([]float64, len(laps)),
+		DurationsMs:    make([]float64, len(laps)),
+		CorrelationIDs: make([]string, len(laps)),
+		Data:           make([]map[string]interface{}, len(laps)),
+	}
+	for i, lap := range laps {
+		cols.States[i] = lap.state
+		cols.StartedAt[i] = lap.end.Add(-lap.duration)
+		cols.OffsetsMs[i] = milliseconds(lap.offset)
+		cols.DurationsMs[i] = milliseconds(lap.duration)
+		cols.CorrelationIDs[i] = lap.correlationID
+		cols.Data[i] = lap.data
+	}
+	return cols
+}
@@ -0,0 +1,30 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumns(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{state: "parse", end: start.Add(5 * time.Millisecond), duration: 5 * time.Millisecond, correlationID: "req-1"},
+		{state: "db", offset: 5 * time.Millisecond, end: start.Add(15 * time.Millisecond), duration: 10 * time.Millisecond,
+			data: map[string]interface{}{"rows": 2}},
+	}
+
+	cols := sw.Columns()
+	assert.Equal(t, 2, cols.Len())
+	assert.Equal(t, []string{"parse", "db"}, cols.States)
+	assert.Equal(t, []time.Time{start, start.Add(5 * time.Millisecond)}, cols.StartedAt)
+	assert.Equal(t, []float64{0, 5}, cols.OffsetsMs)
+	assert.Equal(t, []float64{5, 10}, cols.DurationsMs)
+	assert.Equal(t, []string{"req-1", ""}, cols.CorrelationIDs)
+	assert.Nil(t, cols.Data[0])
+	assert.Equal(t, 2, cols.Data[1]["rows"])
+
+	assert.Zero(t, New(0, true).Columns().Len())
+}
@@ -0,0 +1,48 @@
+package stopwatch
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// WriteCompressed writes laps as in FormattingModeNDJSON compressed with gzip, lap by lap,
+// so dumps of long jobs are stored and shipped cheaply. Read them back with ReadCompressed.
+// zstd is left out to keep the package free of dependencies.
+func (s *Stopwatch) WriteCompressed(w io.Writer) error {
+	s.rlock()
+	schema := s.schemaVersion()
+	s.runlock()
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	for _, lap := range s.Laps() {
+		detailed := newDetailedLap(lap)
+		detailed.Schema = schema
+		if err := enc.Encode(detailed); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ReadCompressed reads laps written by WriteCompressed
+func ReadCompressed(r io.Reader) ([]DetailedLap, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var laps []DetailedLap
+	dec := json.NewDecoder(zr)
+	for dec.More() {
+		var lap DetailedLap
+		if err := dec.Decode(&lap); err != nil {
+			return nil, err
+		}
+		laps = append(laps, lap)
+	}
+	return laps, nil
+}
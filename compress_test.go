@@ -0,0 +1,41 @@
+package stopwatch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCompressed(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFormattingMode(FormattingModeNDJSON)
+	for i := 0; i < 1000; i++ {
+		sw.laps = append(sw.laps, Lap{state: "db", offset: time.Duration(i) * time.Millisecond, duration: time.Millisecond, data: map[string]interface{}{"rows": 2}})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteCompressed(&buf))
+	assert.Less(t, buf.Len(), len(sw.String())/10)
+
+	zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	plain, err := ioutil.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, sw.String(), string(plain))
+
+	laps, err := ReadCompressed(&buf)
+	assert.NoError(t, err)
+	if assert.Len(t, laps, 1000) {
+		assert.Equal(t, DetailedLap{State: "db", Ms: 1, OffsetMs: 999, Data: map[string]interface{}{"rows": 2.0}}, laps[999])
+	}
+}
+
+func TestReadCompressedError(t *testing.T) {
+	_, err := ReadCompressed(strings.NewReader("not gzip"))
+	assert.Error(t, err)
+}
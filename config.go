@@ -0,0 +1,204 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config declares stopwatch behavior, so it can live alongside the rest of the app config
+type Config struct {
+	// Mode is the formatting mode, default is FormattingModeJsonArray
+	Mode FormattingMode `json:"mode" yaml:"mode"`
+	// Precision is the number of decimal places of milliseconds, default is 3
+	Precision *int `json:"precision" yaml:"precision"`
+	// MaxLaps limits the number of stored laps, zero means no limit
+	MaxLaps int `json:"max_laps" yaml:"max_laps"`
+	// Enabled turns lap recording on or off, nil keeps the default or STOPWATCH_ENABLED
+	Enabled *bool `json:"enabled" yaml:"enabled"`
+	// Thresholds are budgets and the slow lap threshold
+	Thresholds ThresholdsConfig `json:"thresholds" yaml:"thresholds"`
+	// Sinks receive every lap, see AddSink
+	Sinks []SinkConfig `json:"sinks" yaml:"sinks"`
+}
+
+// ThresholdsConfig declares budgets, see SetBudget, and slow laps, see SetSlowLapProfile
+type ThresholdsConfig struct {
+	// Total is the budget of the whole run, zero means no limit
+	Total ConfigDuration `json:"total" yaml:"total"`
+	// Laps are budgets of single laps by lap state
+	Laps map[string]ConfigDuration `json:"laps" yaml:"laps"`
+	// AlertWebhook receives alerts of exceeded budgets, see WebhookAlert.
+	// It is required when a budget is set.
+	AlertWebhook string `json:"alert_webhook" yaml:"alert_webhook"`
+	// SlowLap snapshots goroutines of laps longer than it, zero turns it off
+	SlowLap ConfigDuration `json:"slow_lap" yaml:"slow_lap"`
+}
+
+// Sink types of SinkConfig
+const (
+	// SinkTypeFile appends laps to a file, see NewFileSink
+	SinkTypeFile = "file"
+	// SinkTypeStdout and SinkTypeStderr write laps as JSON lines, see NewNDJSONSink
+	SinkTypeStdout = "stdout"
+	SinkTypeStderr = "stderr"
+)
+
+// SinkConfig declares a sink
+type SinkConfig struct {
+	// Type is one of SinkTypeFile, SinkTypeStdout or SinkTypeStderr
+	Type string `json:"type" yaml:"type"`
+	// Path is the file of SinkTypeFile
+	Path string `json:"path" yaml:"path"`
+	// Sync flushes every lap of SinkTypeFile to disk
+	Sync bool `json:"sync" yaml:"sync"`
+}
+
+// ConfigDuration is a time.Duration written like "1.5s" in JSON and YAML
+type ConfigDuration time.Duration
+
+// UnmarshalJSON accepts a duration string, or a number of nanoseconds like time.Duration
+func (d *ConfigDuration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case string:
+		return d.parse(value)
+	case float64:
+		*d = ConfigDuration(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+}
+
+// UnmarshalYAML accepts a duration string, or a number of nanoseconds like time.Duration
+func (d *ConfigDuration) UnmarshalYAML(node *yaml.Node) error {
+	var nanoseconds int64
+	if err := node.Decode(&nanoseconds); err == nil {
+		*d = ConfigDuration(nanoseconds)
+		return nil
+	}
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	return d.parse(value)
+}
+
+func (d *ConfigDuration) parse(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = ConfigDuration(duration)
+	return nil
+}
+
+// ConfigFromJSON parses a JSON document into a Config
+func ConfigFromJSON(data []byte) (Config, error) {
+	var cfg Config
+	err := json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// ConfigFromYAML parses a YAML document into a Config
+func ConfigFromYAML(data []byte) (Config, error) {
+	var cfg Config
+	err := yaml.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// NewFromConfig creates an active stopwatch configured by cfg.
+// Values set in cfg take precedence over the environment.
+// An error is returned for an unknown sink type, a sink file that can't be opened,
+// or a budget without an alert webhook.
+func NewFromConfig(cfg Config) (*Stopwatch, error) {
+	return NewFromConfigWithOffset(cfg, 0, true)
+}
+
+// NewFromConfigWithOffset is NewFromConfig for a stopwatch with a start offset and activity, see New.
+func NewFromConfigWithOffset(cfg Config, offset time.Duration, active bool) (*Stopwatch, error) {
+	sinks, err := cfg.sinks()
+	if err != nil {
+		return nil, err
+	}
+	budget := cfg.Thresholds.budget()
+	if (budget.Total > 0 || len(budget.Laps) > 0) && cfg.Thresholds.AlertWebhook == "" {
+		return nil, fmt.Errorf("budget without an alert webhook")
+	}
+
+	sw := New(offset, active)
+	cfg.apply(sw)
+	for _, sink := range sinks {
+		sw.AddSink(sink)
+	}
+	if cfg.Thresholds.AlertWebhook != "" {
+		sw.SetBudget(budget, WebhookAlert(nil, cfg.Thresholds.AlertWebhook))
+	}
+	return sw, nil
+}
+
+func (c Config) apply(sw *Stopwatch) {
+	if c.Mode != "" {
+		sw.SetFormattingMode(c.Mode)
+	}
+	if c.Precision != nil {
+		sw.SetPrecision(*c.Precision)
+	}
+	sw.SetMaxLaps(c.MaxLaps)
+	if c.Enabled != nil {
+		sw.SetEnabled(*c.Enabled)
+	}
+	if c.Thresholds.SlowLap > 0 {
+		sw.SetSlowLapProfile(time.Duration(c.Thresholds.SlowLap), false)
+	}
+}
+
+func (c Config) sinks() ([]Sink, error) {
+	sinks := make([]Sink, 0, len(c.Sinks))
+	for i, sinkCfg := range c.Sinks {
+		switch sinkCfg.Type {
+		case SinkTypeFile:
+			sink, err := NewFileSink(sinkCfg.Path, sinkCfg.Sync)
+			if err != nil {
+				closeSinks(sinks)
+				return nil, fmt.Errorf("sink %d: %w", i, err)
+			}
+			sinks = append(sinks, sink)
+		case SinkTypeStdout:
+			sinks = append(sinks, NewNDJSONSink(os.Stdout))
+		case SinkTypeStderr:
+			sinks = append(sinks, NewNDJSONSink(os.Stderr))
+		default:
+			closeSinks(sinks)
+			return nil, fmt.Errorf("sink %d: unknown type %q", i, sinkCfg.Type)
+		}
+	}
+	return sinks, nil
+}
+
+// closeSinks releases files of sinks opened before a config error
+func closeSinks(sinks []Sink) {
+	for _, sink := range sinks {
+		if file, ok := sink.(*FileSink); ok {
+			_ = file.file.Close()
+		}
+	}
+}
+
+func (t ThresholdsConfig) budget() Budget {
+	budget := Budget{Total: time.Duration(t.Total)}
+	if len(t.Laps) > 0 {
+		budget.Laps = make(map[string]time.Duration, len(t.Laps))
+		for state, limit := range t.Laps {
+			budget.Laps[state] = time.Duration(limit)
+		}
+	}
+	return budget
+}
@@ -0,0 +1,114 @@
+package stopwatch
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromJSON(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"mode":"JSON_OBJECT_MS","precision":1,"max_laps":2}`))
+	assert.NoError(t, err)
+
+	sw, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+	sw.Lap("lap3")
+
+	assert.Equal(t, FormattingModeJsonMsObject, sw.formattingMode)
+	assert.Equal(t, 1, sw.precision)
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 2)
+	assert.Equal(t, "lap2", laps[0].State())
+	assert.Equal(t, "lap3", laps[1].State())
+}
+
+func TestConfigFromYAML(t *testing.T) {
+	cfg, err := ConfigFromYAML([]byte("mode: JSON_OBJECT\nenabled: false\n"))
+	assert.NoError(t, err)
+
+	sw, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	sw.Lap("lap1")
+
+	assert.Equal(t, FormattingModeJsonSimpleObject, sw.formattingMode)
+	assert.Equal(t, defaultPrecision, sw.precision)
+	assert.Empty(t, sw.Laps())
+}
+
+func TestConfigFromJSONInvalid(t *testing.T) {
+	_, err := ConfigFromJSON([]byte(`{"max_laps":"many"}`))
+	assert.Error(t, err)
+}
+
+func TestConfigEnabledOverridesEnv(t *testing.T) {
+	env := parseEnv(lookupFrom(map[string]string{EnvEnabled: "false"}))
+	enabled := true
+
+	sw := New(0, true)
+	env.apply(sw)
+	Config{Enabled: &enabled}.apply(sw)
+	sw.Lap("lap1")
+	assert.Len(t, sw.Laps(), 1)
+
+	sw = New(0, true)
+	env.apply(sw)
+	Config{}.apply(sw)
+	sw.Lap("lap1")
+	assert.Empty(t, sw.Laps())
+}
+
+func TestConfigThresholds(t *testing.T) {
+	cfg, err := ConfigFromYAML([]byte(`
+thresholds:
+  total: 1m
+  laps:
+    db: 1.5s
+  alert_webhook: http://localhost/alerts
+  slow_lap: 2s
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, ConfigDuration(time.Minute), cfg.Thresholds.Total)
+	assert.Equal(t, ConfigDuration(1500*time.Millisecond), cfg.Thresholds.Laps["db"])
+
+	jsonCfg, err := ConfigFromJSON([]byte(`{"thresholds":{"total":"1m","laps":{"db":"1.5s"},"alert_webhook":"http://localhost/alerts","slow_lap":2000000000}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, jsonCfg)
+
+	sw, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	defer sw.Close(context.Background())
+	assert.Equal(t, 2*time.Second, sw.slowLap)
+	assert.Equal(t, Budget{Total: time.Minute, Laps: map[string]time.Duration{"db": 1500 * time.Millisecond}}, sw.budgetWatch.budget)
+
+	_, err = ConfigFromJSON([]byte(`{"thresholds":{"total":"soon"}}`))
+	assert.Error(t, err)
+
+	_, err = NewFromConfig(Config{Thresholds: ThresholdsConfig{Total: ConfigDuration(time.Minute)}})
+	assert.Error(t, err)
+}
+
+func TestConfigSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "laps.ndjson")
+	cfg, err := ConfigFromJSON([]byte(`{"sinks":[{"type":"file","path":` + jsonString(path) + `}]}`))
+	assert.NoError(t, err)
+
+	sw, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	sw.Lap("lap1")
+	assert.NoError(t, sw.Close(context.Background()))
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"state":"lap1"`))
+
+	_, err = NewFromConfig(Config{Sinks: []SinkConfig{{Type: "kafka"}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package stopwatch
+
+import (
+	"context"
+	"sync"
+)
+
+// Data keys of laps recorded with LapWithContext
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// TraceExtractor returns IDs of the active trace and span in the context, if any.
+// For OpenTelemetry it can be written as
+//
+//	func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	}
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+// SetTraceExtractor makes LapWithContext record trace and span IDs found in the context,
+// so stopwatch dumps in logs can be joined with traces
+func (s *Stopwatch) SetTraceExtractor(extractor TraceExtractor) {
+	s.lock()
+	defer s.unlock()
+	s.traceExtractor = extractor
+}
+
+// AttributeKey is a context key of a lap attribute, see SetContextAttributes:
+//
+//	ctx = context.WithValue(ctx, stopwatch.AttributeKey("tenant"), "acme")
+type AttributeKey string
+
+// AttributeExtractor returns the value of an attribute found in the context.
+// For OpenTelemetry baggage it can be written as
+//
+//	func(ctx context.Context, key string) (interface{}, bool) {
+//		member := baggage.FromContext(ctx).Member(key)
+//		return member.Value(), member.Key() != ""
+//	}
+type AttributeExtractor func(ctx context.Context, key string) (value interface{}, ok bool)
+
+// SetContextAttributes makes LapWithContext copy attributes with allowed keys from the context
+// to lap data, so tenant, user or feature flag dimensions appear on laps without plumbing.
+// A nil extractor reads context values set with AttributeKey. Lap data passed explicitly wins.
+func (s *Stopwatch) SetContextAttributes(extractor AttributeExtractor, keys ...string) {
+	if extractor == nil {
+		extractor = contextValue
+	}
+
+	s.lock()
+	defer s.unlock()
+	s.attrExtractor = extractor
+	s.attrKeys = keys
+}
+
+func contextValue(ctx context.Context, key string) (interface{}, bool) {
+	value := ctx.Value(AttributeKey(key))
+	return value, value != nil
+}
+
+// LapWithContext starts a new lap like LapWithData and adds IDs of the active
+// trace and span to the lap data, see SetTraceExtractor, and context attributes,
+// see SetContextAttributes
+func (s *Stopwatch) LapWithContext(ctx context.Context, state string, data map[string]interface{}) Lap {
+	now := s.now()
+
+	s.rlock()
+	extractor := s.traceExtractor
+	attributeExtractor, keys := s.attrExtractor, s.attrKeys
+	s.runlock()
+
+	if extractor != nil {
+		if traceID, spanID, ok := extractor(ctx); ok {
+			data = copyData(data, 2)
+			data[TraceIDKey] = traceID
+			data[SpanIDKey] = spanID
+		}
+	}
+
+	copied := false
+	for _, key := range keys {
+		if _, found := data[key]; found {
+			continue
+		}
+		value, ok := attributeExtractor(ctx, key)
+		if !ok {
+			continue
+		}
+		if !copied {
+			data = copyData(data, len(keys))
+			copied = true
+		}
+		data[key] = value
+	}
+
+	return s.LapWithDataAndTime(now, state, data)
+}
+
+type stopwatchKey struct{}
+
+// NewContext returns a copy of ctx carrying the stopwatch, see FromContext
+func NewContext(ctx context.Context, sw *Stopwatch) context.Context {
+	return context.WithValue(ctx, stopwatchKey{}, sw)
+}
+
+var (
+	disabledOnce      sync.Once
+	disabledStopwatch *Stopwatch
+)
+
+// FromContext returns the stopwatch carried by ctx, see NewContext.
+// If there is none, it returns a shared disabled stopwatch, so call sites don't need
+// nil checks and hot paths don't create one per call. Don't change its settings.
+func FromContext(ctx context.Context) *Stopwatch {
+	if sw, ok := ctx.Value(stopwatchKey{}).(*Stopwatch); ok && sw != nil {
+		return sw
+	}
+	disabledOnce.Do(func() {
+		// it's never changed, so it's read without locking by any goroutine
+		disabledStopwatch = New(0, false).WithoutLocking()
+		disabledStopwatch.disabled = true
+	})
+	return disabledStopwatch
+}
+
+// LapCtx starts a new lap of the stopwatch carried by ctx like LapWithContext,
+// so deep call stacks can record laps without passing the stopwatch around
+func LapCtx(ctx context.Context, state string) Lap {
+	return FromContext(ctx).LapWithContext(ctx, state, nil)
+}
+
+// copyData copies lap data with room for extra keys, so the caller's map is never modified
+func copyData(data map[string]interface{}, extra int) map[string]interface{} {
+	result := make(map[string]interface{}, len(data)+extra)
+	for k, v := range data {
+		result[k] = v
+	}
+	return result
+}
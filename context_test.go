@@ -0,0 +1,86 @@
+package stopwatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type spanKey struct{}
+
+func TestLapWithContext(t *testing.T) {
+	sw := New(0, true)
+	sw.SetTraceExtractor(func(ctx context.Context) (string, string, bool) {
+		ids, ok := ctx.Value(spanKey{}).([2]string)
+		return ids[0], ids[1], ok
+	})
+
+	ctx := context.WithValue(context.Background(), spanKey{}, [2]string{"trace1", "span1"})
+	data := map[string]interface{}{"rows": 2}
+	sw.LapWithContext(ctx, "db", data)
+	sw.LapWithContext(context.Background(), "render", nil)
+
+	laps := sw.Laps()
+	assert.Equal(t, map[string]interface{}{"rows": 2, TraceIDKey: "trace1", SpanIDKey: "span1"}, laps[0].data)
+	assert.Nil(t, laps[1].data)
+	assert.Len(t, data, 1, "caller's data must not be modified")
+}
+
+func TestLapWithContextWithoutExtractor(t *testing.T) {
+	sw := New(0, true)
+	sw.LapWithContext(context.Background(), "db", nil)
+
+	assert.Nil(t, sw.Laps()[0].data)
+}
+
+func TestSetContextAttributes(t *testing.T) {
+	sw := New(0, true)
+	sw.SetContextAttributes(nil, "tenant", "flag")
+
+	ctx := context.WithValue(context.Background(), AttributeKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, AttributeKey("user"), "bob")
+	data := map[string]interface{}{"rows": 2}
+	lap := sw.LapWithContext(ctx, "query", data)
+	assert.Equal(t, map[string]interface{}{"rows": 2, "tenant": "acme"}, lap.data)
+	assert.Len(t, data, 1, "the caller's map is not modified")
+
+	lap = sw.LapWithContext(ctx, "query", map[string]interface{}{"tenant": "explicit"})
+	assert.Equal(t, "explicit", lap.data["tenant"])
+
+	assert.Nil(t, sw.LapWithContext(context.Background(), "query", nil).data)
+}
+
+func TestSetContextAttributesExtractor(t *testing.T) {
+	sw := New(0, true)
+	sw.SetContextAttributes(func(ctx context.Context, key string) (interface{}, bool) {
+		return key + "-value", key == "flag"
+	}, "flag", "tenant")
+
+	lap := sw.LapWithContext(context.Background(), "query", nil)
+	assert.Equal(t, map[string]interface{}{"flag": "flag-value"}, lap.data)
+}
+
+func TestStopwatchInContext(t *testing.T) {
+	sw := New(0, true)
+	ctx := NewContext(context.Background(), sw)
+	assert.Same(t, sw, FromContext(ctx))
+
+	LapCtx(ctx, "db")
+	assert.Equal(t, "db", sw.Laps()[0].State())
+
+	noop := FromContext(context.Background())
+	LapCtx(context.Background(), "db")
+	noop.Lap("render")
+	assert.Empty(t, noop.Laps())
+	assert.Same(t, noop, FromContext(context.Background()), "shared")
+	assert.Zero(t, testing.AllocsPerRun(10, func() { FromContext(context.Background()) }))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		LapCtx(context.Background(), "other goroutine")
+	}()
+	LapCtx(context.Background(), "db")
+	<-done
+}
@@ -0,0 +1,23 @@
+package stopwatch
+
+// CorrelationIDKey is the field holding the correlation ID in every formatting mode
+const CorrelationIDKey = "correlation_id"
+
+// SetCorrelationID sets an ID, e.g. of a request, included in every formatting mode and into laps
+// passed to sinks, so stopwatch output can be joined with the rest of the request's logs.
+// Object modes get it as a top-level field, array modes and sinks get it in every lap.
+func (s *Stopwatch) SetCorrelationID(id string) {
+	s.lock()
+	defer s.unlock()
+	s.correlationID = id
+	for i := range s.laps {
+		s.laps[i].correlationID = id
+	}
+}
+
+// correlationIDSnapshot reads the correlation ID under the read lock, the caller must not hold it
+func (s *Stopwatch) correlationIDSnapshot() string {
+	s.rlock()
+	defer s.runlock()
+	return s.correlationID
+}
@@ -0,0 +1,54 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.AddSink(NewNDJSONSink(&buf))
+	sw.Lap("lap1")
+	sw.SetCorrelationID("req-1")
+	sw.Lap("lap2")
+
+	assert.Contains(t, buf.String(), `"correlation_id":"req-1","state":"lap2"`)
+
+	for _, mode := range []FormattingMode{
+		FormattingModeJsonSimpleObject,
+		FormattingModeJsonMsObject,
+		FormattingModeJsonIntObject,
+		FormattingModeJsonFull,
+	} {
+		sw.SetFormattingMode(mode)
+		result := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(sw.String()), &result), mode)
+		assert.Equal(t, "req-1", result[CorrelationIDKey], mode)
+	}
+
+	for _, mode := range []FormattingMode{
+		FormattingModeJsonArray,
+		FormattingModeJsonDetailed,
+	} {
+		sw.SetFormattingMode(mode)
+		var result []map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(sw.String()), &result), mode)
+		for _, lap := range result {
+			assert.Equal(t, "req-1", lap[CorrelationIDKey], mode)
+		}
+	}
+}
+
+func TestNoCorrelationID(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("lap1")
+
+	sw.SetFormattingMode(FormattingModeJsonDetailed)
+	assert.NotContains(t, sw.String(), CorrelationIDKey)
+	sw.SetFormattingMode(FormattingModeJsonSimpleObject)
+	assert.NotContains(t, sw.String(), CorrelationIDKey)
+}
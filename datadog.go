@@ -0,0 +1,184 @@
+package stopwatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultDatadogAgentURL is the traces endpoint of a local Datadog agent
+const DefaultDatadogAgentURL = "http://localhost:8126/v0.3/traces"
+
+// DatadogConfig configures DatadogExporter
+type DatadogConfig struct {
+	// AgentURL is the traces endpoint, DefaultDatadogAgentURL if empty
+	AgentURL string
+	// Service is the service name of spans, unless a lap has it in ServiceKey data
+	Service string
+	// Name is the name of the root span and lap spans, "stopwatch" if empty
+	Name string
+	// ServiceKey and ResourceKey are lap data keys with service and resource of a lap span,
+	// "service" and "resource" if empty. Resource defaults to the lap state.
+	ServiceKey, ResourceKey string
+	// Client sends the payload, http.DefaultClient if nil
+	Client *http.Client
+	// Retry repeats failed requests, no retries by default
+	Retry RetryPolicy
+}
+
+// DatadogExporter sends stopwatches to Datadog APM as traces: a root span for the whole
+// stopwatch and a child span per lap. Laps with "error" data, like those of WithError,
+// MeasureRetry or TimedGroup, are error spans with the message in "error.message" meta.
+// It uses the public trace API of the agent, so no tracing SDK is required.
+type DatadogExporter struct {
+	cfg DatadogConfig
+}
+
+// NewDatadogExporter creates an exporter, see DatadogConfig for defaults
+func NewDatadogExporter(cfg DatadogConfig) *DatadogExporter {
+	if cfg.AgentURL == "" {
+		cfg.AgentURL = DefaultDatadogAgentURL
+	}
+	if cfg.Name == "" {
+		cfg.Name = "stopwatch"
+	}
+	if cfg.ServiceKey == "" {
+		cfg.ServiceKey = "service"
+	}
+	if cfg.ResourceKey == "" {
+		cfg.ResourceKey = "resource"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &DatadogExporter{cfg: cfg}
+}
+
+// Close releases idle connections. Export sends traces right away, so nothing is pending.
+func (e *DatadogExporter) Close(ctx context.Context) error {
+	e.cfg.Client.CloseIdleConnections()
+	return nil
+}
+
+// datadogSpan follows the span format of the agent trace API
+type datadogSpan struct {
+	TraceID  uint64            `json:"trace_id"`
+	SpanID   uint64            `json:"span_id"`
+	ParentID uint64            `json:"parent_id,omitempty"`
+	Name     string            `json:"name"`
+	Resource string            `json:"resource"`
+	Service  string            `json:"service"`
+	Type     string            `json:"type"`
+	Start    int64             `json:"start"`    // unix nanoseconds
+	Duration int64             `json:"duration"` // nanoseconds
+	Error    int32             `json:"error"`
+	Meta     map[string]string `json:"meta,omitempty"`
+}
+
+// Export sends laps of the stopwatch as a single trace
+func (e *DatadogExporter) Export(ctx context.Context, sw *Stopwatch) error {
+	laps := sw.Laps()
+	if len(laps) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal([][]datadogSpan{e.spans(laps, sw.correlationIDSnapshot(), sw.RunID())})
+	if err != nil {
+		return err
+	}
+
+	return countExport(e.cfg.Retry.do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, e.cfg.AgentURL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Datadog-Trace-Count", "1")
+
+		resp, err := e.cfg.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return &StatusError{Service: "datadog agent", StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	}))
+}
+
+func (e *DatadogExporter) spans(laps []Lap, correlationID, runID string) []datadogSpan {
+	traceID := randomID()
+	start := laps[0].end.Add(-laps[0].duration)
+	end := laps[len(laps)-1].end
+
+	root := datadogSpan{
+		TraceID:  traceID,
+		SpanID:   randomID(),
+		Name:     e.cfg.Name,
+		Resource: e.cfg.Name,
+		Service:  e.cfg.Service,
+		Type:     "custom",
+		Start:    start.UnixNano(),
+		Duration: end.Sub(start).Nanoseconds(),
+	}
+	if correlationID != "" || runID != "" {
+		root.Meta = make(map[string]string, 2)
+	}
+	if correlationID != "" {
+		root.Meta[CorrelationIDKey] = correlationID
+	}
+	if runID != "" {
+		root.Meta[RunIDKey] = runID
+	}
+
+	spans := []datadogSpan{root}
+	for _, lap := range laps {
+		span := datadogSpan{
+			TraceID:  traceID,
+			SpanID:   randomID(),
+			ParentID: root.SpanID,
+			Name:     e.cfg.Name + ".lap",
+			Resource: lap.state,
+			Service:  e.cfg.Service,
+			Type:     "custom",
+			Start:    lap.end.Add(-lap.duration).UnixNano(),
+			Duration: lap.duration.Nanoseconds(),
+		}
+		for k, v := range lap.data {
+			switch k {
+			case e.cfg.ServiceKey:
+				span.Service = fmt.Sprint(v)
+			case e.cfg.ResourceKey:
+				span.Resource = fmt.Sprint(v)
+			case "error":
+				span.Error = 1
+				if span.Meta == nil {
+					span.Meta = make(map[string]string, len(lap.data))
+				}
+				span.Meta["error.message"] = fmt.Sprint(v)
+			default:
+				if span.Meta == nil {
+					span.Meta = make(map[string]string, len(lap.data))
+				}
+				span.Meta[k] = fmt.Sprint(v)
+			}
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// randomID generates span and trace IDs, Datadog needs them positive in int64 range
+func randomID() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano()) >> 1
+	}
+	return binary.BigEndian.Uint64(b[:]) >> 1
+}
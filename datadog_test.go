@@ -0,0 +1,70 @@
+package stopwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatadogExporter(t *testing.T) {
+	var traces [][]datadogSpan
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "1", r.Header.Get("X-Datadog-Trace-Count"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&traces))
+	}))
+	defer server.Close()
+
+	end := time.Now()
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{state: "parse", end: end.Add(-10 * time.Millisecond), duration: 5 * time.Millisecond},
+		{state: "db", end: end, duration: 10 * time.Millisecond, data: map[string]interface{}{
+			"service": "postgres", "resource": "SELECT users", "rows": 2}},
+		{state: "cache", end: end, data: map[string]interface{}{"error": "connection refused"}},
+	}
+
+	exporter := NewDatadogExporter(DatadogConfig{AgentURL: server.URL, Service: "api"})
+	assert.NoError(t, exporter.Export(context.Background(), sw))
+
+	assert.Len(t, traces, 1)
+	spans := traces[0]
+	assert.Len(t, spans, 4)
+
+	root := spans[0]
+	assert.Equal(t, "stopwatch", root.Name)
+	assert.Equal(t, "api", root.Service)
+	assert.Equal(t, (15 * time.Millisecond).Nanoseconds(), root.Duration)
+
+	assert.Equal(t, root.SpanID, spans[1].ParentID)
+	assert.Equal(t, root.TraceID, spans[1].TraceID)
+	assert.Equal(t, "parse", spans[1].Resource)
+	assert.Equal(t, "api", spans[1].Service)
+
+	assert.Equal(t, "SELECT users", spans[2].Resource)
+	assert.Equal(t, "postgres", spans[2].Service)
+	assert.Equal(t, map[string]string{"rows": "2"}, spans[2].Meta)
+	assert.Equal(t, end.Add(-10*time.Millisecond).UnixNano(), spans[2].Start)
+	assert.Zero(t, spans[2].Error)
+
+	assert.EqualValues(t, 1, spans[3].Error)
+	assert.Equal(t, map[string]string{"error.message": "connection refused"}, spans[3].Meta)
+}
+
+func TestDatadogExporterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sw := New(0, true)
+	sw.Lap("lap1")
+
+	exporter := NewDatadogExporter(DatadogConfig{AgentURL: server.URL})
+	assert.Error(t, exporter.Export(context.Background(), sw))
+}
@@ -0,0 +1,149 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// SchemaVersion is the version of DetailedLap and FullStopwatch, see SetSchema.
+// Fields may be added within a version, never removed or changed.
+const SchemaVersion = "stopwatch/v2"
+
+// SetSchema makes the detailed modes include "schema": SchemaVersion,
+// in every lap of FormattingModeJsonDetailed and FormattingModeNDJSON,
+// and on the top level of FormattingModeJsonFull
+func (s *Stopwatch) SetSchema(enabled bool) {
+	s.lock()
+	defer s.unlock()
+	s.schema = enabled
+}
+
+// DetailedLap is a lap in FormattingModeJsonDetailed, FormattingModeNDJSON and NDJSON sinks.
+// Parsers can unmarshal the output into it.
+type DetailedLap struct {
+	// Schema is SchemaVersion if enabled with SetSchema
+	Schema        string                 `json:"schema,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	State         string                 `json:"state"`
+	Ms            float64                `json:"ms"`
+	OffsetMs      float64                `json:"offset_ms"` // from the stopwatch start to the lap start
+	Data          map[string]interface{} `json:"data,omitempty"`
+	Seq           uint64                 `json:"seq,omitempty"`
+	RunID         string                 `json:"run_id,omitempty"`
+}
+
+func newDetailedLap(lap Lap) DetailedLap {
+	return DetailedLap{
+		CorrelationID: lap.correlationID,
+		State:         lap.state,
+		Ms:            milliseconds(lap.duration),
+		OffsetMs:      milliseconds(lap.offset),
+		Data:          lap.data,
+		Seq:           lap.seq,
+		RunID:         lap.runID,
+	}
+}
+
+// schemaVersion must be called under the read lock
+func (s *Stopwatch) schemaVersion() string {
+	if s.schema {
+		return SchemaVersion
+	}
+	return ""
+}
+
+// formatDetailed must be called under the read lock
+func (s *Stopwatch) formatDetailed(laps []Lap) (string, error) {
+	detailed := make([]DetailedLap, len(laps))
+	for i, lap := range laps {
+		detailed[i] = newDetailedLap(lap)
+		detailed[i].Schema = s.schemaVersion()
+	}
+
+	result, err := json.Marshal(detailed)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// milliseconds converts a duration to milliseconds with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
+}
+
+// FullStopwatch is the stopwatch in FormattingModeJsonFull.
+// Parsers can unmarshal the output into it.
+type FullStopwatch struct {
+	// Schema is SchemaVersion if enabled with SetSchema
+	Schema        string        `json:"schema,omitempty"`
+	CorrelationID string        `json:"correlation_id,omitempty"`
+	RunID         string        `json:"run_id,omitempty"`
+	Running       bool          `json:"running"`
+	StartedAt     time.Time     `json:"started_at"`
+	StoppedAt     *time.Time    `json:"stopped_at,omitempty"`
+	ElapsedMs     float64       `json:"elapsed_ms"`
+	PausedMs      float64       `json:"paused_ms"`
+	AdjustedMs    float64       `json:"adjusted_ms,omitempty"`
+	SLAMs         float64       `json:"sla_ms,omitempty"`
+	WithinSLA     *bool         `json:"within_sla,omitempty"`
+	Laps          []DetailedLap `json:"laps"`
+	// RollupsMs are totals of every level of dotted states, see Stopwatch.Rollups
+	RollupsMs map[string]float64 `json:"rollups_ms,omitempty"`
+	// Pauses are periods the stopwatch was stopped, with reasons given to Stopwatch.Pause
+	Pauses []DetailedPause `json:"pauses,omitempty"`
+}
+
+// formatFull must be called under the read lock
+func (s *Stopwatch) formatFull() (string, error) {
+	full := FullStopwatch{
+		Schema:        s.schemaVersion(),
+		CorrelationID: s.correlationID,
+		RunID:         s.runID,
+		Running:       s.active(),
+		StartedAt:     s.startedAtLocked(),
+		ElapsedMs:     milliseconds(s.ElapsedTime()),
+		PausedMs:      milliseconds(s.paused),
+		AdjustedMs:    milliseconds(s.adjusted),
+		Laps:          make([]DetailedLap, len(s.laps)),
+		RollupsMs:     rollupsMs(s.laps),
+		Pauses:        s.detailedPauses(),
+	}
+	if s.sla > 0 {
+		within := s.ElapsedTime() <= s.sla
+		full.SLAMs = milliseconds(s.sla)
+		full.WithinSLA = &within
+	}
+	if !full.Running {
+		stop := s.stop
+		full.StoppedAt = &stop
+	}
+	for i, lap := range s.laps {
+		full.Laps[i] = newDetailedLap(lap)
+		full.Laps[i].CorrelationID = "" // it's on the top level already
+		if lap.runID == s.runID {
+			full.Laps[i].RunID = ""
+		}
+	}
+
+	result, err := json.Marshal(full)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// formatNDJSON must be called under the read lock
+func (s *Stopwatch) formatNDJSON(laps []Lap) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, lap := range laps {
+		detailed := newDetailedLap(lap)
+		detailed.Schema = s.schemaVersion()
+		if err := enc.Encode(detailed); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,107 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailedFormatting(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFormattingMode(FormattingModeJsonDetailed)
+	sw.laps = []Lap{
+		{state: `say "hi"`, duration: 4500 * time.Microsecond},
+		{state: "db", offset: 4500 * time.Microsecond, duration: 12300 * time.Microsecond, data: map[string]interface{}{"rows": 2}},
+	}
+
+	assert.Equal(t,
+		`[{"state":"say \"hi\"","ms":4.5,"offset_ms":0},{"state":"db","ms":12.3,"offset_ms":4.5,"data":{"rows":2}}]`,
+		sw.String())
+}
+
+func TestDetailedFormattingRecordsOffsets(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFormattingMode(FormattingModeJsonDetailed)
+	sw.Start()
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+
+	var laps []DetailedLap
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &laps))
+	assert.Len(t, laps, 2)
+	assert.Equal(t, 0.0, laps[0].OffsetMs)
+	assert.Equal(t, laps[0].Ms, laps[1].OffsetMs)
+}
+
+func TestDetailedFormattingError(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeJsonDetailed)
+	sw.LapWithData("lap1", map[string]interface{}{"callback": func() {}})
+
+	_, err := json.Marshal(sw)
+	assert.Error(t, err)
+	assert.Contains(t, sw.String(), `"error"`)
+}
+
+func TestFullFormatting(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	created := sw.stop
+
+	time.Sleep(2 * time.Millisecond)
+	sw.Start()
+	sw.Lap("lap1")
+
+	var full FullStopwatch
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	assert.True(t, full.Running)
+	assert.Nil(t, full.StoppedAt)
+	assert.True(t, full.PausedMs >= 2, "paused %f ms", full.PausedMs)
+	assert.WithinDuration(t, created, full.StartedAt, time.Millisecond)
+	assert.Len(t, full.Laps, 1)
+
+	sw.Stop()
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	assert.False(t, full.Running)
+	assert.NotNil(t, full.StoppedAt)
+
+	sw.Reset(0, true)
+	assert.Zero(t, sw.paused)
+}
+
+func TestDetailedFormattingSchema(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{{state: "db", duration: time.Millisecond}}
+
+	sw.SetFormattingMode(FormattingModeJsonDetailed)
+	assert.Equal(t, `[{"state":"db","ms":1,"offset_ms":0}]`, sw.String())
+
+	sw.SetSchema(true)
+	assert.Equal(t, `[{"schema":"stopwatch/v2","state":"db","ms":1,"offset_ms":0}]`, sw.String())
+
+	sw.SetFormattingMode(FormattingModeNDJSON)
+	assert.Equal(t, `{"schema":"stopwatch/v2","state":"db","ms":1,"offset_ms":0}`+"\n", sw.String())
+
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	var full FullStopwatch
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	assert.Equal(t, SchemaVersion, full.Schema)
+	if assert.Len(t, full.Laps, 1) {
+		assert.Empty(t, full.Laps[0].Schema) // it's on the top level already
+	}
+}
+
+func TestNDJSONMarshalJSON(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeNDJSON)
+	sw.Lap("db")
+	sw.Lap("render")
+
+	encoded, err := json.Marshal(struct{ Timings *Stopwatch }{sw})
+	assert.NoError(t, err)
+	var decoded struct{ Timings FullStopwatch }
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Len(t, decoded.Timings.Laps, 2)
+}
@@ -0,0 +1,98 @@
+package stopwatch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicateKeys is how the JSON object modes handle laps sharing a state
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysAsIs repeats the key for every lap, as it always did. Many parsers keep
+	// only one of the values, some reject such objects.
+	DuplicateKeysAsIs DuplicateKeys = iota
+	// DuplicateKeysIndex suffixes repeated keys with the index of the lap among laps with the key,
+	// starting with 2: {"db":10, "db_2":20}
+	DuplicateKeysIndex
+	// DuplicateKeysLast keeps the last lap of a key
+	DuplicateKeysLast
+	// DuplicateKeysSum sums laps of a key up
+	DuplicateKeysSum
+	// DuplicateKeysArray renders every key as an array of its laps: {"db":[10,20], "render":[5]}
+	DuplicateKeysArray
+)
+
+// SetDuplicateKeys sets how the JSON object modes handle laps sharing a state
+func (s *Stopwatch) SetDuplicateKeys(policy DuplicateKeys) {
+	s.lock()
+	defer s.unlock()
+	s.duplicateKeys = policy
+}
+
+type objectField struct {
+	key, value string
+	// raw is what the value was rendered from: a Lap, []Lap of DuplicateKeysArray,
+	// or a flattened data value, []interface{} of DuplicateKeysArray
+	raw interface{}
+}
+
+// objectFields must be called under the read lock
+func (s *Stopwatch) objectFields(value func(Lap) string) []objectField {
+	fields := make([]objectField, 0, len(s.laps))
+	switch s.duplicateKeys {
+	case DuplicateKeysAsIs:
+		for _, lap := range s.laps {
+			key := s.objectKey(lap.state)
+			fields = append(fields, objectField{key, value(lap), lap})
+			fields = s.appendFlattened(fields, key, lap)
+		}
+
+	case DuplicateKeysIndex:
+		seen := make(map[string]int, len(s.laps))
+		for _, lap := range s.laps {
+			key := s.objectKey(lap.state)
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key = fmt.Sprintf("%s_%d", key, n)
+			}
+			fields = append(fields, objectField{key, value(lap), lap})
+			fields = s.appendFlattened(fields, key, lap)
+		}
+
+	default:
+		var keys []string
+		groups := make(map[string][]Lap, len(s.laps))
+		for _, lap := range s.laps {
+			key := s.objectKey(lap.state)
+			if _, found := groups[key]; !found {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], lap)
+		}
+
+		for _, key := range keys {
+			laps := groups[key]
+			last := laps[len(laps)-1]
+			switch s.duplicateKeys {
+			case DuplicateKeysSum:
+				for _, lap := range laps[:len(laps)-1] {
+					last.duration += lap.duration
+				}
+				fields = append(fields, objectField{key, value(last), last})
+				fields = s.appendFlattened(fields, key, last)
+			case DuplicateKeysArray:
+				values := make([]string, len(laps))
+				for i, lap := range laps {
+					values[i] = value(lap)
+				}
+				fields = append(fields, objectField{key, "[" + strings.Join(values, ",") + "]", laps})
+				fields = s.appendFlattenedArray(fields, key, laps)
+			default:
+				fields = append(fields, objectField{key, value(last), last})
+				fields = s.appendFlattened(fields, key, last)
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,34 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDuplicateKeys(t *testing.T) {
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{formatter: defaultFormatter, state: "db", duration: 10 * time.Millisecond},
+		{formatter: defaultFormatter, state: "render", duration: 5 * time.Millisecond},
+		{formatter: defaultFormatter, state: "db", duration: 20 * time.Millisecond},
+	}
+	sw.SetFormattingMode(FormattingModeJsonIntObject)
+	sw.SetIntegerUnit(time.Millisecond)
+
+	for policy, expected := range map[DuplicateKeys]string{
+		DuplicateKeysAsIs:  `{"db":10, "render":5, "db":20}`,
+		DuplicateKeysIndex: `{"db":10, "render":5, "db_2":20}`,
+		DuplicateKeysLast:  `{"db":20, "render":5}`,
+		DuplicateKeysSum:   `{"db":30, "render":5}`,
+		DuplicateKeysArray: `{"db":[10,20], "render":[5]}`,
+	} {
+		sw.SetDuplicateKeys(policy)
+		assert.Equal(t, expected, sw.String(), policy)
+	}
+
+	sw.SetDuplicateKeys(DuplicateKeysSum)
+	sw.SetFormattingMode(FormattingModeJsonSimpleObject)
+	assert.Equal(t, `{"db":"30ms", "render":"5ms"}`, sw.String())
+}
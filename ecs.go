@@ -0,0 +1,86 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ecsVersion is the version of Elastic Common Schema the documents follow
+const ecsVersion = "8.11"
+
+type ecsDocument struct {
+	Timestamp time.Time         `json:"@timestamp"`
+	ECS       ecsVersionField   `json:"ecs"`
+	Event     ecsEvent          `json:"event"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Trace     *ecsID            `json:"trace,omitempty"`
+	Span      *ecsID            `json:"span,omitempty"`
+}
+
+type ecsVersionField struct {
+	Version string `json:"version"`
+}
+
+type ecsEvent struct {
+	Action   string    `json:"action"`
+	Duration int64     `json:"duration"` // nanoseconds
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+type ecsID struct {
+	ID string `json:"id"`
+}
+
+func newECSDocument(lap Lap) ecsDocument {
+	doc := ecsDocument{
+		Timestamp: lap.end,
+		ECS:       ecsVersionField{Version: ecsVersion},
+		Event: ecsEvent{
+			Action:   lap.state,
+			Duration: lap.duration.Nanoseconds(),
+			Start:    lap.end.Add(-lap.duration),
+			End:      lap.end,
+		},
+	}
+
+	// ECS labels are keywords, so values are converted to strings
+	if len(lap.data) > 0 || lap.correlationID != "" || lap.runID != "" {
+		doc.Labels = make(map[string]string, len(lap.data)+2)
+	}
+	for k, v := range lap.data {
+		switch k {
+		case TraceIDKey:
+			doc.Trace = &ecsID{ID: fmt.Sprint(v)}
+		case SpanIDKey:
+			doc.Span = &ecsID{ID: fmt.Sprint(v)}
+		default:
+			doc.Labels[k] = fmt.Sprint(v)
+		}
+	}
+	if lap.correlationID != "" {
+		doc.Labels[CorrelationIDKey] = lap.correlationID
+	}
+	if lap.runID != "" {
+		doc.Labels[RunIDKey] = lap.runID
+	}
+	if len(doc.Labels) == 0 {
+		doc.Labels = nil
+	}
+	return doc
+}
+
+// formatECS must be called under the read lock
+func (s *Stopwatch) formatECS(laps []Lap) (string, error) {
+	docs := make([]ecsDocument, len(laps))
+	for i, lap := range laps {
+		docs[i] = newECSDocument(lap)
+	}
+
+	result, err := json.Marshal(docs)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
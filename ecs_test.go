@@ -0,0 +1,49 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECSFormatting(t *testing.T) {
+	end := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeECS)
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{
+		{state: "db", end: end, duration: 12 * time.Millisecond, correlationID: "req-1",
+			data: map[string]interface{}{"rows": 2, TraceIDKey: "t1", SpanIDKey: "s1"}},
+		{state: "render", end: end, duration: time.Millisecond},
+	}
+
+	assert.JSONEq(t, `[
+		{
+			"@timestamp": "2021-03-04T05:06:07Z",
+			"ecs": {"version": "8.11"},
+			"event": {"action": "db", "duration": 12000000, "start": "2021-03-04T05:06:06.988Z", "end": "2021-03-04T05:06:07Z"},
+			"labels": {"rows": "2", "correlation_id": "req-1"},
+			"trace": {"id": "t1"},
+			"span": {"id": "s1"}
+		},
+		{
+			"@timestamp": "2021-03-04T05:06:07Z",
+			"ecs": {"version": "8.11"},
+			"event": {"action": "render", "duration": 1000000, "start": "2021-03-04T05:06:06.999Z", "end": "2021-03-04T05:06:07Z"}
+		}
+	]`, sw.String())
+}
+
+func TestECSFormattingRecordsTimes(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeECS)
+	before := time.Now()
+	sw.Lap("lap1")
+
+	var docs []ecsDocument
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &docs))
+	assert.Len(t, docs, 1)
+	assert.WithinDuration(t, before, docs[0].Event.End, time.Second)
+}
@@ -0,0 +1,85 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DefaultCloudWatchNamespace is the namespace of metrics in FormattingModeCloudWatchEMF
+const DefaultCloudWatchNamespace = "Stopwatch"
+
+// CloudWatchMetricPrefix prefixes lap states to make metric names in FormattingModeCloudWatchEMF,
+// so a lap state can't overwrite "_aws" or other members of the document
+const CloudWatchMetricPrefix = "lap_"
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"` // unix milliseconds
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// SetCloudWatchNamespace sets the namespace of metrics in FormattingModeCloudWatchEMF
+func (s *Stopwatch) SetCloudWatchNamespace(namespace string) {
+	s.lock()
+	defer s.unlock()
+	s.emfNamespace = namespace
+}
+
+// formatCloudWatchEMF must be called under the read lock
+func (s *Stopwatch) formatCloudWatchEMF() (string, error) {
+	namespace := s.emfNamespace
+	if namespace == "" {
+		namespace = DefaultCloudWatchNamespace
+	}
+
+	// laps with the same state become an array of values of a single metric
+	values := map[string][]float64{}
+	metrics := []emfMetric{} // CloudWatch rejects null metrics
+	for _, lap := range s.laps {
+		name := CloudWatchMetricPrefix + lap.state
+		if _, found := values[name]; !found {
+			metrics = append(metrics, emfMetric{Name: name, Unit: "Milliseconds"})
+		}
+		values[name] = append(values[name], milliseconds(lap.duration))
+	}
+
+	doc := map[string]interface{}{
+		"_aws": emfMetadata{
+			Timestamp: s.now().UnixNano() / int64(time.Millisecond),
+			CloudWatchMetrics: []emfDirective{{
+				Namespace:  namespace,
+				Dimensions: [][]string{{}},
+				Metrics:    metrics,
+			}},
+		},
+	}
+	for name, metricValues := range values {
+		if len(metricValues) == 1 {
+			doc[name] = metricValues[0]
+		} else {
+			doc[name] = metricValues
+		}
+	}
+	if s.correlationID != "" {
+		doc[CorrelationIDKey] = s.correlationID // a property, not a dimension, to keep cardinality low
+	}
+	if s.runID != "" {
+		doc[RunIDKey] = s.runID
+	}
+
+	result, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
@@ -0,0 +1,71 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudWatchEMFFormatting(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := NewWithClock(0, true, clock)
+	sw.SetFormattingMode(FormattingModeCloudWatchEMF)
+	sw.SetCloudWatchNamespace("MyApp")
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{
+		{state: "db", duration: 2 * time.Millisecond},
+		{state: "render", duration: time.Millisecond},
+		{state: "db", duration: 3 * time.Millisecond},
+	}
+
+	doc := map[string]json.RawMessage{}
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &doc))
+
+	var metadata emfMetadata
+	assert.NoError(t, json.Unmarshal(doc["_aws"], &metadata))
+	assert.Equal(t, clock.now.UnixNano()/int64(time.Millisecond), metadata.Timestamp)
+	assert.Equal(t, []emfDirective{{
+		Namespace:  "MyApp",
+		Dimensions: [][]string{{}},
+		Metrics:    []emfMetric{{Name: "lap_db", Unit: "Milliseconds"}, {Name: "lap_render", Unit: "Milliseconds"}},
+	}}, metadata.CloudWatchMetrics)
+
+	assert.JSONEq(t, `[2, 3]`, string(doc["lap_db"]))
+	assert.JSONEq(t, `1`, string(doc["lap_render"]))
+	assert.JSONEq(t, `"req-1"`, string(doc[CorrelationIDKey]))
+}
+
+func TestCloudWatchEMFDefaultNamespace(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeCloudWatchEMF)
+	sw.Lap("db")
+
+	assert.Contains(t, sw.String(), `"Namespace":"Stopwatch"`)
+}
+
+func TestCloudWatchEMFReservedStates(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeCloudWatchEMF)
+	sw.SetRunID("run-1")
+	sw.laps = []Lap{
+		{state: "_aws", duration: time.Millisecond},
+		{state: RunIDKey, duration: time.Millisecond},
+	}
+
+	doc := map[string]json.RawMessage{}
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &doc))
+	var metadata emfMetadata
+	assert.NoError(t, json.Unmarshal(doc["_aws"], &metadata), "the metadata is not overwritten")
+	assert.JSONEq(t, `"run-1"`, string(doc[RunIDKey]))
+	assert.JSONEq(t, `1`, string(doc["lap__aws"]))
+	assert.JSONEq(t, `1`, string(doc["lap_run_id"]))
+}
+
+func TestCloudWatchEMFWithoutLaps(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeCloudWatchEMF)
+
+	assert.Contains(t, sw.String(), `"Metrics":[]`)
+}
@@ -0,0 +1,73 @@
+package stopwatch
+
+import (
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Environment variables overriding defaults of every stopwatch created by New
+const (
+	// EnvFormattingMode sets the formatting mode, e.g. STOPWATCH_MODE=JSON_OBJECT_MS
+	EnvFormattingMode = "STOPWATCH_MODE"
+	// EnvPrecision sets decimal places of milliseconds, e.g. STOPWATCH_PRECISION=1
+	EnvPrecision = "STOPWATCH_PRECISION"
+	// EnvEnabled turns lap recording on or off, e.g. STOPWATCH_ENABLED=false
+	EnvEnabled = "STOPWATCH_ENABLED"
+)
+
+// envConfig holds defaults read from the environment. Nil fields are not set.
+type envConfig struct {
+	mode      *FormattingMode
+	precision *int
+	enabled   *bool
+}
+
+var (
+	envOnce   sync.Once
+	envLoaded envConfig
+)
+
+// envDefaults reads the environment once per process
+func envDefaults() envConfig {
+	envOnce.Do(func() {
+		envLoaded = parseEnv(os.LookupEnv)
+	})
+	return envLoaded
+}
+
+// parseEnv ignores malformed values, so a typo in deployment config can't break the app
+func parseEnv(lookup func(string) (string, bool)) envConfig {
+	var cfg envConfig
+
+	if value, ok := lookup(EnvFormattingMode); ok && value != "" {
+		mode := FormattingMode(value)
+		cfg.mode = &mode
+	}
+
+	if value, ok := lookup(EnvPrecision); ok {
+		if precision, err := strconv.Atoi(value); err == nil && precision >= 0 {
+			cfg.precision = &precision
+		}
+	}
+
+	if value, ok := lookup(EnvEnabled); ok {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			cfg.enabled = &enabled
+		}
+	}
+
+	return cfg
+}
+
+func (c envConfig) apply(sw *Stopwatch) {
+	if c.mode != nil {
+		sw.SetFormattingMode(*c.mode)
+	}
+	if c.precision != nil {
+		sw.SetPrecision(*c.precision)
+	}
+	if c.enabled != nil {
+		sw.SetEnabled(*c.enabled)
+	}
+}
@@ -0,0 +1,41 @@
+package stopwatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	cfg := parseEnv(lookupFrom(map[string]string{
+		EnvFormattingMode: "JSON_OBJECT_MS",
+		EnvPrecision:      "1",
+		EnvEnabled:        "false",
+	}))
+
+	sw := New(0, true)
+	cfg.apply(sw)
+	sw.Lap("lap1")
+
+	assert.Equal(t, FormattingModeJsonMsObject, sw.formattingMode)
+	assert.Equal(t, 1, sw.precision)
+	assert.Empty(t, sw.Laps())
+}
+
+func TestParseEnvIgnoresMalformed(t *testing.T) {
+	cfg := parseEnv(lookupFrom(map[string]string{
+		EnvPrecision: "many",
+		EnvEnabled:   "maybe",
+	}))
+
+	assert.Nil(t, cfg.mode)
+	assert.Nil(t, cfg.precision)
+	assert.Nil(t, cfg.enabled)
+}
@@ -0,0 +1,72 @@
+package stopwatch
+
+import (
+	"fmt"
+	"time"
+)
+
+// Equal reports whether two laps have the same state and data,
+// and their durations and offsets differ by no more than tolerance.
+// Data values are compared by their textual form, so 2 and 2.0 read back from JSON are equal.
+func (l Lap) Equal(other Lap, tolerance time.Duration) bool {
+	return l.state == other.state &&
+		withinTolerance(l.duration, other.duration, tolerance) &&
+		withinTolerance(l.offset, other.offset, tolerance) &&
+		equalData(l.data, other.data)
+}
+
+// Equal reports whether two stopwatches are in the same running state and have equal laps,
+// see Lap.Equal. Elapsed times of stopped stopwatches must differ by no more than tolerance.
+// Formatters and settings are not compared.
+func Equal(a, b *Stopwatch, tolerance time.Duration) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	aActive, aElapsed, aLaps := a.state()
+	bActive, bElapsed, bLaps := b.state()
+
+	if aActive != bActive || len(aLaps) != len(bLaps) {
+		return false
+	}
+	if !aActive && !withinTolerance(aElapsed, bElapsed, tolerance) {
+		return false
+	}
+	for i := range aLaps {
+		if !aLaps[i].Equal(bLaps[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// state is taken under the lock of a single stopwatch, so comparing two can't deadlock.
+// Laps are copied, as they are changed in place, e.g. by SetCorrelationID.
+func (s *Stopwatch) state() (active bool, elapsed time.Duration, laps []Lap) {
+	s.rlock()
+	defer s.runlock()
+	laps = make([]Lap, len(s.laps))
+	copy(laps, s.laps)
+	return s.active(), s.ElapsedTime(), laps
+}
+
+func withinTolerance(a, b, tolerance time.Duration) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
+
+func equalData(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, va := range a {
+		vb, found := b[k]
+		if !found || fmt.Sprint(va) != fmt.Sprint(vb) {
+			return false
+		}
+	}
+	return true
+}
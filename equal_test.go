@@ -0,0 +1,56 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLapEqual(t *testing.T) {
+	lap := Lap{state: "db", offset: time.Millisecond, duration: 10 * time.Millisecond, data: map[string]interface{}{"rows": 2}}
+
+	assert.True(t, lap.Equal(lap, 0))
+	assert.True(t, lap.Equal(Lap{state: "db", offset: time.Millisecond, duration: 10*time.Millisecond + time.Microsecond, data: map[string]interface{}{"rows": 2.0}}, time.Microsecond))
+	assert.False(t, lap.Equal(Lap{state: "db", offset: time.Millisecond, duration: 11 * time.Millisecond, data: lap.data}, time.Microsecond))
+	assert.False(t, lap.Equal(Lap{state: "cache", offset: time.Millisecond, duration: 10 * time.Millisecond, data: lap.data}, 0))
+	assert.False(t, lap.Equal(Lap{state: "db", offset: time.Millisecond, duration: 10 * time.Millisecond}, 0))
+}
+
+func TestEqual(t *testing.T) {
+	newStopped := func(elapsed time.Duration, states ...string) *Stopwatch {
+		sw := New(0, false)
+		sw.stop = sw.start.Add(elapsed)
+		for i, state := range states {
+			sw.laps = append(sw.laps, Lap{state: state, offset: time.Duration(i) * time.Millisecond, duration: time.Millisecond})
+		}
+		return sw
+	}
+
+	a := newStopped(2*time.Millisecond, "parse", "db")
+	assert.True(t, Equal(a, a, 0))
+	assert.True(t, Equal(a, newStopped(2*time.Millisecond+time.Microsecond, "parse", "db"), time.Microsecond))
+	assert.False(t, Equal(a, newStopped(3*time.Millisecond, "parse", "db"), time.Microsecond))
+	assert.False(t, Equal(a, newStopped(2*time.Millisecond, "parse"), 0))
+	assert.False(t, Equal(a, New(0, true), time.Hour))
+	assert.False(t, Equal(a, nil, 0))
+	assert.True(t, Equal(nil, nil, 0))
+}
+
+func TestEqualConcurrent(t *testing.T) {
+	a, b := New(0, true), New(0, true)
+	a.Lap("parse")
+	b.Lap("parse")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			a.SetCorrelationID("req-1")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		Equal(a, b, time.Second)
+	}
+	<-done
+}
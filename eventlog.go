@@ -0,0 +1,107 @@
+package stopwatch
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind is the kind of Event
+type EventKind string
+
+const (
+	// EventStart is logged when the stopwatch starts or resumes counting
+	EventStart EventKind = "start"
+	// EventStop is logged when the stopwatch stops counting
+	EventStop EventKind = "stop"
+	// EventLap is logged for every lap, kept or not
+	EventLap EventKind = "lap"
+	// EventTask is logged for every TimedGroup or MultiLap task, kept or not
+	EventTask EventKind = "task"
+	// EventReset is logged on Reset, the stopwatch is stopped with no laps at its time
+	EventReset EventKind = "reset"
+	// EventAdjust is logged by AddElapsed and SubtractElapsed
+	EventAdjust EventKind = "adjust"
+	// EventMark is logged when the current lap is made to start later, e.g. by MeasureRetry,
+	// the time since the previous lap is not recorded
+	EventMark EventKind = "mark"
+)
+
+// Event is an entry of the append-only log of a stopwatch, see SetEventLog and Replay
+type Event struct {
+	Kind  EventKind              `json:"kind"`
+	Time  time.Time              `json:"time"`
+	State string                 `json:"state,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	// Delta is the adjustment of EventAdjust, or the duration of EventTask
+	Delta time.Duration `json:"delta,omitempty"`
+	// Reason is the pause reason of EventStop, see Stopwatch.Pause
+	Reason string `json:"reason,omitempty"`
+}
+
+// SetEventLog calls log for every start, stop, lap, lap start mark, adjustment and reset of the stopwatch,
+// outside of the stopwatch lock. The log starts with the current state: a reset and
+// a start event, and a stop event if the stopwatch is stopped. Laps recorded before
+// are not logged, so set it right after New. Nil turns the log off.
+func (s *Stopwatch) SetEventLog(log func(Event)) {
+	s.lock()
+	s.eventLog = log
+	start, stop, active := s.start, s.stop, s.active()
+	s.unlock()
+
+	if log == nil {
+		return
+	}
+	for _, event := range currentEvents(start, stop, active) {
+		log(event)
+	}
+}
+
+// currentEvents describe a stopwatch without laps
+func currentEvents(start, stop time.Time, active bool) []Event {
+	events := resetEvents(start, true)
+	if !active {
+		events = append(events, Event{Kind: EventStop, Time: stop})
+	}
+	return events
+}
+
+func resetEvents(start time.Time, active bool) []Event {
+	events := []Event{{Kind: EventReset, Time: start}}
+	if active {
+		events = append(events, Event{Kind: EventStart, Time: start})
+	}
+	return events
+}
+
+// Replay rebuilds a stopwatch from its event log, e.g. to audit a historical run
+// or render it in another formatting mode. The stopwatch is stopped unless
+// the log ends with it running.
+func Replay(events []Event) (*Stopwatch, error) {
+	sw := New(0, false)
+	if len(events) > 0 {
+		sw.start, sw.stop = events[0].Time, events[0].Time
+	}
+
+	for i, event := range events {
+		switch event.Kind {
+		case EventStart:
+			sw.startAt(event.Time)
+		case EventStop:
+			sw.stopAt(event.Time, event.Reason)
+		case EventLap:
+			sw.LapWithDataAndTime(event.Time, event.State, event.Data)
+		case EventTask:
+			sw.recordTask(event.State, event.Time.Add(-event.Delta), event.Time, event.Data)
+		case EventAdjust:
+			sw.adjustAt(event.Time, event.Delta)
+		case EventMark:
+			sw.markAt(event.Time)
+		case EventReset:
+			sw.Reset(0, false)
+			sw.start, sw.stop = event.Time, event.Time
+		default:
+			return nil, fmt.Errorf("event %d: unknown kind %q", i, event.Kind)
+		}
+	}
+	return sw, nil
+}
@@ -0,0 +1,61 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	var events []Event
+	sw := New(0, true)
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+
+	time.Sleep(time.Millisecond)
+	sw.LapWithData("parse", map[string]interface{}{"rows": 2})
+	sw.Stop()
+	time.Sleep(time.Millisecond)
+	sw.Start()
+	sw.Lap("save")
+	sw.Stop()
+
+	kinds := make([]EventKind, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind
+	}
+	assert.Equal(t, []EventKind{EventReset, EventStart, EventLap, EventStop, EventStart, EventLap, EventStop}, kinds)
+
+	// events survive a round trip through JSON, like an audit log
+	encoded, err := json.Marshal(events)
+	assert.NoError(t, err)
+	var decoded []Event
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	replayed, err := Replay(decoded)
+	assert.NoError(t, err)
+	// JSON drops monotonic clock readings, so wall clock differences may show up
+	assert.True(t, Equal(sw, replayed, 100*time.Microsecond))
+	assert.Equal(t, 2.0, replayed.Laps()[0].data["rows"])
+}
+
+func TestReplayReset(t *testing.T) {
+	var events []Event
+	sw := New(0, true)
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+	sw.Lap("before")
+	sw.Reset(time.Second, true)
+	sw.Lap("after")
+
+	replayed, err := Replay(events)
+	assert.NoError(t, err)
+	assert.Len(t, replayed.Laps(), 1)
+	assert.Equal(t, "after", replayed.Laps()[0].State())
+	assert.True(t, sw.Laps()[0].Equal(replayed.Laps()[0], 100*time.Microsecond))
+}
+
+func TestReplayUnknownEvent(t *testing.T) {
+	_, err := Replay([]Event{{Kind: "split"}})
+	assert.EqualError(t, err, `event 0: unknown kind "split"`)
+}
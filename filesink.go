@@ -0,0 +1,75 @@
+package stopwatch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileSink appends every lap to a file as a JSON line, in the same form as NewNDJSONSink,
+// so a crash in the middle of a job still leaves the laps recorded so far
+type FileSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	sync   bool
+	closed bool
+}
+
+// NewFileSink opens the file for appending, creating it if needed.
+// With sync every lap is flushed to disk before the lap returns, that survives
+// a power loss but costs a disk round trip per lap.
+func NewFileSink(path string, sync bool) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file, sync: sync}, nil
+}
+
+// WriteLap appends the lap with a single write, so records of concurrent laps don't interleave
+func (f *FileSink) WriteLap(lap Lap) error {
+	record, err := json.Marshal(newDetailedLap(lap))
+	if err != nil {
+		return err
+	}
+	record = append(record, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+	if _, err := f.file.Write(record); err != nil {
+		return err
+	}
+	if f.sync {
+		return f.file.Sync()
+	}
+	return nil
+}
+
+// Flush flushes written laps to disk
+func (f *FileSink) Flush(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close flushes written laps to disk and closes the file
+func (f *FileSink) Close(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	if err := f.file.Sync(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
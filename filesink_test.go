@@ -0,0 +1,56 @@
+package stopwatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "laps.ndjson")
+
+	sink, err := NewFileSink(path, true)
+	assert.NoError(t, err)
+	sw := New(0, true)
+	sw.AddSink(sink)
+	sw.Lap("lap1")
+	assert.NoError(t, sw.Close(context.Background()))
+
+	// a restarted job appends to the same file
+	sink, err = NewFileSink(path, false)
+	assert.NoError(t, err)
+	sw = New(0, true)
+	sw.AddSink(sink)
+	sw.LapWithData("lap2", map[string]interface{}{"rows": 2})
+	assert.NoError(t, sink.Flush(context.Background()))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var states []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var lap DetailedLap
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &lap))
+		states = append(states, lap.State)
+	}
+	assert.Equal(t, []string{"lap1", "lap2"}, states)
+
+	assert.NoError(t, sink.Close(context.Background()))
+	assert.Equal(t, ErrClosed, sink.WriteLap(Lap{state: "lap3"}))
+}
+
+func TestFileSinkOpenError(t *testing.T) {
+	_, err := NewFileSink(filepath.Join("does", "not", "exist"), false)
+	assert.Error(t, err)
+}
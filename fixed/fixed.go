@@ -0,0 +1,191 @@
+// Package fixed provides an allocation-free stopwatch with a fixed number of laps.
+// It has no maps and does not use fmt, so it compiles under TinyGo for microcontroller targets.
+// The API mirrors github.com/alexus1024/stopwatch.
+package fixed
+
+import (
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxLaps is the number of laps a Stopwatch can hold
+const MaxLaps = 16
+
+// Lap represents a split time from the stopwatch
+type Lap struct {
+	State    string
+	Duration time.Duration
+}
+
+// Stopwatch is a timer holding up to MaxLaps laps. Laps recorded when it is full are dropped.
+// The zero value is a stopped stopwatch, call Reset or Start to use it.
+type Stopwatch struct {
+	start, stop time.Time
+	mark        time.Duration
+	laps        [MaxLaps]Lap
+	count       int
+	dropped     int
+	mu          sync.Mutex
+}
+
+// New creates a new stopwatch with starting time offset by a user defined value
+func New(offset time.Duration, active bool) *Stopwatch {
+	var sw Stopwatch
+	sw.Reset(offset, active)
+	return &sw
+}
+
+// Reset allows the re-use of a Stopwatch instead of creating a new one
+func (s *Stopwatch) Reset(offset time.Duration, active bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start = now.Add(-offset)
+	if active {
+		s.stop = time.Time{}
+	} else {
+		s.stop = now
+	}
+	s.mark = 0
+	s.count = 0
+	s.dropped = 0
+}
+
+func (s *Stopwatch) active() bool {
+	return s.stop.IsZero() && !s.start.IsZero()
+}
+
+// Start intiates, or resumes the counting up process
+func (s *Stopwatch) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.start.IsZero() {
+		s.start = time.Now()
+		s.stop = time.Time{}
+		return
+	}
+	if !s.active() {
+		s.start = s.start.Add(time.Since(s.stop))
+		s.stop = time.Time{}
+	}
+}
+
+// Stop makes the stopwatch stop counting up
+func (s *Stopwatch) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active() {
+		s.stop = time.Now()
+	}
+}
+
+// ElapsedTime is the time the stopwatch has been active
+func (s *Stopwatch) ElapsedTime() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elapsed(time.Now())
+}
+
+func (s *Stopwatch) elapsed(now time.Time) time.Duration {
+	if s.active() {
+		return now.Sub(s.start)
+	}
+	return s.stop.Sub(s.start)
+}
+
+// Lap starts a new lap, and returns the length of the previous one
+func (s *Stopwatch) Lap(state string) time.Duration {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elapsed := s.elapsed(now)
+	duration := elapsed - s.mark
+	s.mark = elapsed
+	if s.count == MaxLaps {
+		s.dropped++
+		return duration
+	}
+	s.laps[s.count] = Lap{State: state, Duration: duration}
+	s.count++
+	return duration
+}
+
+// LapAt returns the lap number i, i must be less than Len()
+func (s *Stopwatch) LapAt(i int) Lap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.laps[i]
+}
+
+// Len returns the number of recorded laps
+func (s *Stopwatch) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Dropped returns the number of laps not recorded because the stopwatch was full
+func (s *Stopwatch) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// AppendJSON appends laps to buf in the form {"Lap1":10123, "Lap2":20234} with values in microseconds.
+// Pass a buffer with enough capacity to avoid allocations.
+func (s *Stopwatch) AppendJSON(buf []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buf = append(buf, '{')
+	for i := 0; i < s.count; i++ {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = appendJSONString(buf, s.laps[i].State)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, s.laps[i].Duration.Microseconds(), 10)
+	}
+	return append(buf, '}')
+}
+
+// appendJSONString appends s quoted and escaped for JSON like encoding/json without HTML escaping,
+// invalid UTF-8 is replaced with U+FFFD
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			// valid JSON, but not JavaScript
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,77 @@
+package fixed
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaps(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("parse")
+	sw.Lap("db")
+
+	assert.Equal(t, 2, sw.Len())
+	assert.Equal(t, "parse", sw.LapAt(0).State)
+	assert.Equal(t, "db", sw.LapAt(1).State)
+}
+
+func TestOverflow(t *testing.T) {
+	sw := New(0, true)
+	for i := 0; i < MaxLaps+3; i++ {
+		sw.Lap("lap")
+	}
+
+	assert.Equal(t, MaxLaps, sw.Len())
+	assert.Equal(t, 3, sw.Dropped())
+
+	sw.Reset(0, true)
+	assert.Zero(t, sw.Len())
+	assert.Zero(t, sw.Dropped())
+}
+
+func TestZeroValue(t *testing.T) {
+	var sw Stopwatch
+	assert.Zero(t, sw.ElapsedTime())
+
+	sw.Start()
+	time.Sleep(time.Millisecond)
+	sw.Stop()
+	assert.True(t, sw.ElapsedTime() >= time.Millisecond)
+}
+
+func TestAppendJSON(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap(`say "hi"`)
+	sw.Lap("db")
+
+	result := map[string]float64{}
+	assert.NoError(t, json.Unmarshal(sw.AppendJSON(nil), &result))
+	assert.Contains(t, result, `say "hi"`)
+	assert.Contains(t, result, "db")
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{"db", `say "hi"`, `C:\temp`, "a\x01b\n\t", "bad \xff utf-8", "ok вг", "line\u2028sep", "<html>&"} {
+		var expected bytes.Buffer
+		enc := json.NewEncoder(&expected)
+		enc.SetEscapeHTML(false)
+		assert.NoError(t, enc.Encode(s))
+		assert.Equal(t, strings.TrimSuffix(expected.String(), "\n"), string(appendJSONString(nil, s)), s)
+	}
+}
+
+func TestLapDoesNotAllocate(t *testing.T) {
+	sw := New(0, true)
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		sw.Reset(0, true)
+		sw.Lap("lap")
+		buf = sw.AppendJSON(buf[:0])
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,109 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// flatField is a column of FormattingModeFlat
+type flatField struct {
+	key   string
+	value interface{}
+}
+
+// formatFlat must be called under the read lock
+func (s *Stopwatch) formatFlat() (string, error) {
+	var runID interface{} // null without a run or correlation ID
+	switch {
+	case s.runID != "":
+		runID = s.runID
+	case s.correlationID != "":
+		runID = s.correlationID
+	}
+	times := s.flatTimes()
+	fields := []flatField{
+		{"run_id", runID},
+		times[0], // total_ms
+		{"started_at", s.startedAtLocked()},
+	}
+	fields = append(fields, times[1:]...)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(`"` + field.key + `":`)
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.String(), nil
+}
+
+// flatTimes returns total_ms, a column per lap state and columns of children
+// prefixed with "child_" and their names, like "child_db_total_ms".
+// It must be called under the read lock.
+func (s *Stopwatch) flatTimes() []flatField {
+	fields := []flatField{{"total_ms", milliseconds(s.ElapsedTime())}}
+
+	var children []flatField
+	for _, child := range s.children {
+		prefix := "child_" + flatName(child.name) + "_"
+		child.sw.rlock()
+		for _, field := range child.sw.flatTimes() {
+			children = append(children, flatField{prefix + field.key, field.value})
+		}
+		child.sw.runlock()
+	}
+
+	states, totals := stateTotals(s.laps)
+	columns := make(map[string]int, len(states))
+	reserved := make(map[string]bool, len(children)+3)
+	for _, key := range []string{"run_id", "total_ms", "started_at"} {
+		reserved[key] = true
+	}
+	for _, field := range children {
+		reserved[field.key] = true
+	}
+	for _, state := range states {
+		column := flatColumn(state)
+		if reserved[column] {
+			column = "lap_" + column // e.g. a "total" state doesn't clash with total_ms
+		}
+		if i, found := columns[column]; found {
+			// states differing in symbols only share a column
+			fields[i].value = fields[i].value.(float64) + milliseconds(totals[state])
+			continue
+		}
+		columns[column] = len(fields)
+		fields = append(fields, flatField{column, milliseconds(totals[state])})
+	}
+	return append(fields, children...)
+}
+
+// flatColumn makes a column name of a lap state, which warehouses accept:
+// lower case letters, digits and underscores, not starting with a digit, ending with "_ms"
+func flatColumn(state string) string {
+	column := flatName(state)
+	if column == "" || unicode.IsDigit(rune(column[0])) {
+		column = "_" + column
+	}
+	return column + "_ms"
+}
+
+// flatName makes lower case letters, digits and underscores of a name
+func flatName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, name)
+}
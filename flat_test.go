@@ -0,0 +1,54 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatFlat(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, false)
+	sw.start = start
+	sw.stop = start.Add(30 * time.Millisecond)
+	sw.laps = []Lap{
+		{state: "db.query", duration: 10 * time.Millisecond},
+		{state: "Render", duration: 5 * time.Millisecond},
+		{state: "db.query", duration: 10 * time.Millisecond},
+		{state: "db query", duration: 5 * time.Millisecond},
+	}
+	sw.SetFormattingMode(FormattingModeFlat)
+
+	assert.Contains(t, sw.String(), `{"run_id":"`+sw.RunID()+`",`)
+
+	sw.SetRunID("")
+	assert.Equal(t, `{"run_id":null,"total_ms":30,"started_at":"2021-01-02T03:04:05Z","db_query_ms":25,"render_ms":5}`, sw.String())
+
+	sw.SetCorrelationID("req-1")
+	assert.Contains(t, sw.String(), `{"run_id":"req-1",`)
+}
+
+func TestFlatColumn(t *testing.T) {
+	assert.Equal(t, "fetch_ms", flatColumn("Fetch"))
+	assert.Equal(t, "_2fa_ms", flatColumn("2fa"))
+	assert.Equal(t, "__ms", flatColumn(""))
+	assert.Equal(t, "__b_ms", flatColumn("ü-b"))
+}
+
+func TestFormatFlatReservedColumns(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, false)
+	sw.SetRunID("run-1")
+	sw.start = start
+	sw.stop = start.Add(30 * time.Millisecond)
+	sw.laps = []Lap{
+		{state: "total", duration: 10 * time.Millisecond},
+		{state: "run_id", duration: 5 * time.Millisecond},
+		{state: "started_at", duration: 5 * time.Millisecond},
+	}
+	sw.SetFormattingMode(FormattingModeFlat)
+
+	assert.Equal(t, `{"run_id":"run-1","total_ms":30,"started_at":"2021-01-02T03:04:05Z",`+
+		`"lap_total_ms":10,"run_id_ms":5,"started_at_ms":5}`, sw.String())
+}
@@ -0,0 +1,58 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SetFlattenedData makes the JSON object modes emit lap data of the keys as sibling keys
+// of the lap, named by the lap key and the data key: {"db":"10ms", "db_rows":42}.
+// Log pipelines like ELK get the data without nested objects breaking index mappings.
+// Laps without the data key get no sibling. Calling it without keys turns it off.
+func (s *Stopwatch) SetFlattenedData(keys ...string) {
+	s.lock()
+	defer s.unlock()
+	s.flattenedData = keys
+}
+
+// appendFlattened must be called under the read lock
+func (s *Stopwatch) appendFlattened(fields []objectField, lapKey string, lap Lap) []objectField {
+	for _, key := range s.flattenedData {
+		value, found := lap.data[key]
+		if !found {
+			continue
+		}
+		fields = append(fields, objectField{lapKey + "_" + key, flattenedValue(value), value})
+	}
+	return fields
+}
+
+// appendFlattenedArray adds arrays of data values of the laps, with nulls for laps without them.
+// It must be called under the read lock.
+func (s *Stopwatch) appendFlattenedArray(fields []objectField, lapKey string, laps []Lap) []objectField {
+	for _, key := range s.flattenedData {
+		values := make([]string, len(laps))
+		raw := make([]interface{}, len(laps))
+		found := false
+		for i, lap := range laps {
+			values[i] = "null"
+			if value, ok := lap.data[key]; ok {
+				values[i] = flattenedValue(value)
+				raw[i] = value
+				found = true
+			}
+		}
+		if found {
+			fields = append(fields, objectField{lapKey + "_" + key, "[" + strings.Join(values, ",") + "]", raw})
+		}
+	}
+	return fields
+}
+
+func flattenedValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(err.Error())
+	}
+	return string(encoded)
+}
@@ -0,0 +1,40 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenedData(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFormattingMode(FormattingModeJsonIntObject)
+	sw.SetIntegerUnit(time.Millisecond)
+	sw.laps = []Lap{
+		{state: "db", duration: 10 * time.Millisecond, data: map[string]interface{}{"rows": 42, "query": "SELECT"}},
+		{state: "render", duration: 5 * time.Millisecond},
+		{state: "db", duration: 20 * time.Millisecond, data: map[string]interface{}{"rows": 7}},
+	}
+	sw.SetFlattenedData("rows", "missing")
+
+	assert.Equal(t, `{"db":10, "db_rows":42, "render":5, "db":20, "db_rows":7}`, sw.String())
+
+	sw.SetDuplicateKeys(DuplicateKeysIndex)
+	assert.Equal(t, `{"db":10, "db_rows":42, "render":5, "db_2":20, "db_2_rows":7}`, sw.String())
+
+	sw.SetDuplicateKeys(DuplicateKeysLast)
+	assert.Equal(t, `{"db":20, "db_rows":7, "render":5}`, sw.String())
+
+	sw.SetDuplicateKeys(DuplicateKeysArray)
+	assert.Equal(t, `{"db":[10,20], "db_rows":[42,7], "render":[5]}`, sw.String())
+
+	sw.SetFlattenedData("query")
+	assert.Equal(t, `{"db":[10,20], "db_query":["SELECT",null], "render":[5]}`, sw.String())
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &decoded))
+
+	sw.SetFlattenedData()
+	assert.Equal(t, `{"db":[10,20], "render":[5]}`, sw.String())
+}
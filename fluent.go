@@ -0,0 +1,74 @@
+package stopwatch
+
+import "time"
+
+// WithMode sets the formatting mode like SetFormattingMode and returns the stopwatch,
+// so setup fits into one expression:
+//
+//	sw := stopwatch.New(0, true).WithMode(stopwatch.FormattingModeJsonMsObject).WithPrecision(1)
+func (s *Stopwatch) WithMode(mode FormattingMode) *Stopwatch {
+	s.SetFormattingMode(mode)
+	return s
+}
+
+// WithFormatter sets the formatter like SetFormatter and returns the stopwatch
+func (s *Stopwatch) WithFormatter(formatter func(time.Duration) string) *Stopwatch {
+	s.SetFormatter(formatter)
+	return s
+}
+
+// WithPrecision sets the precision like SetPrecision and returns the stopwatch
+func (s *Stopwatch) WithPrecision(precision int) *Stopwatch {
+	s.SetPrecision(precision)
+	return s
+}
+
+// WithIntegerUnit sets the integer unit like SetIntegerUnit and returns the stopwatch
+func (s *Stopwatch) WithIntegerUnit(unit time.Duration) *Stopwatch {
+	s.SetIntegerUnit(unit)
+	return s
+}
+
+// WithResolution sets the resolution like SetResolution and returns the stopwatch
+func (s *Stopwatch) WithResolution(resolution time.Duration) *Stopwatch {
+	s.SetResolution(resolution)
+	return s
+}
+
+// WithMaxLaps limits kept laps like SetMaxLaps and returns the stopwatch
+func (s *Stopwatch) WithMaxLaps(maxLaps int) *Stopwatch {
+	s.SetMaxLaps(maxLaps)
+	return s
+}
+
+// WithCorrelationID sets the correlation ID like SetCorrelationID and returns the stopwatch
+func (s *Stopwatch) WithCorrelationID(id string) *Stopwatch {
+	s.SetCorrelationID(id)
+	return s
+}
+
+// WithRunID sets the run ID like SetRunID and returns the stopwatch
+func (s *Stopwatch) WithRunID(id string) *Stopwatch {
+	s.SetRunID(id)
+	return s
+}
+
+// WithSink attaches a sink like AddSink and returns the stopwatch
+func (s *Stopwatch) WithSink(sink Sink) *Stopwatch {
+	s.AddSink(sink)
+	return s
+}
+
+// Then records a lap like Lap and returns the stopwatch, so laps can be chained:
+//
+//	sw.Then("parse").Then("validate").Stop()
+func (s *Stopwatch) Then(state string) *Stopwatch {
+	s.Lap(state)
+	return s
+}
+
+// ThenWithData records a lap like LapWithData and returns the stopwatch
+func (s *Stopwatch) ThenWithData(state string, data map[string]interface{}) *Stopwatch {
+	s.LapWithData(state, data)
+	return s
+}
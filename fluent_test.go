@@ -0,0 +1,28 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFluent(t *testing.T) {
+	sw := New(0, true).
+		WithMode(FormattingModeJsonIntObject).
+		WithIntegerUnit(time.Hour).
+		WithMaxLaps(2).
+		WithCorrelationID("req-1").
+		WithRunID("run-1")
+
+	sw.Then("lap1").Then("lap2").ThenWithData("lap3", map[string]interface{}{"rows": 2})
+
+	assert.Equal(t, FormattingModeJsonIntObject, sw.formattingMode)
+	laps := sw.Laps()
+	if assert.Len(t, laps, 2) {
+		assert.Equal(t, "lap2", laps[0].State())
+		assert.Equal(t, "run-1", laps[1].RunID())
+		assert.Equal(t, 2, laps[1].data["rows"])
+	}
+	assert.Equal(t, `{"correlation_id":"req-1", "lap2":0, "lap3":0}`, sw.String())
+}
@@ -0,0 +1,136 @@
+package stopwatch
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Flusher is anything holding timing data that must be written out before exit,
+// like HoneycombSink
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// FlusherFunc adapts a function to the Flusher interface, e.g. to print a stopwatch:
+//
+//	stopwatch.RegisterFlusher(stopwatch.FlusherFunc(func(ctx context.Context) error {
+//		sw.Stop()
+//		log.Println(sw)
+//		return nil
+//	}))
+type FlusherFunc func(ctx context.Context) error
+
+// Flush calls f(ctx)
+func (f FlusherFunc) Flush(ctx context.Context) error {
+	return f(ctx)
+}
+
+var (
+	flushersMu sync.Mutex
+	flushers   = map[*Flusher]struct{}{}
+)
+
+// RegisterFlusher adds the flusher to those flushed by FlushAll and FlushOnExit.
+// Call the returned function when the flusher is done, e.g. when a job finished normally.
+func RegisterFlusher(f Flusher) (unregister func()) {
+	key := &f
+	flushersMu.Lock()
+	flushers[key] = struct{}{}
+	flushersMu.Unlock()
+
+	return func() {
+		flushersMu.Lock()
+		delete(flushers, key)
+		flushersMu.Unlock()
+	}
+}
+
+// FlushAll flushes all registered flushers concurrently and returns the first error.
+// It returns when ctx is done even if flushers are still running, with ctx.Err()
+// unless a flusher failed before.
+func FlushAll(ctx context.Context) error {
+	flushersMu.Lock()
+	list := make([]Flusher, 0, len(flushers))
+	for f := range flushers {
+		list = append(list, *f)
+	}
+	flushersMu.Unlock()
+
+	errs := make(chan error, len(list))
+	for _, f := range list {
+		go func(f Flusher) {
+			errs <- f.Flush(ctx)
+		}(f)
+	}
+
+	var first error
+	for range list {
+		select {
+		case err := <-errs:
+			if err != nil && first == nil {
+				first = err
+			}
+		case <-ctx.Done():
+			if first == nil {
+				first = ctx.Err()
+			}
+			return first
+		}
+	}
+	return first
+}
+
+// FlushOnExit handles SIGINT and SIGTERM by flushing all registered flushers within the timeout
+// and exiting with the conventional 128+signal code, so timings of interrupted jobs aren't lost.
+// Call the returned function to restore default signal handling.
+func FlushOnExit(timeout time.Duration) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			flushAndExit(sig, timeout, os.Exit)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+func flushAndExit(sig os.Signal, timeout time.Duration, exit func(int)) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	_ = FlushAll(ctx)
+	cancel()
+
+	code := 1
+	if s, ok := sig.(syscall.Signal); ok {
+		code = 128 + int(s)
+	}
+	exit(code)
+}
+
+// flushEvery calls flush every interval until stop is closed
+func flushEvery(interval time.Duration, stop <-chan struct{}, flush func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = flush(context.Background())
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package stopwatch
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushAll(t *testing.T) {
+	flushed := make(chan string, 2)
+	unregister1 := RegisterFlusher(FlusherFunc(func(ctx context.Context) error {
+		flushed <- "first"
+		return nil
+	}))
+	unregister2 := RegisterFlusher(FlusherFunc(func(ctx context.Context) error {
+		flushed <- "second"
+		return errors.New("failed")
+	}))
+
+	assert.EqualError(t, FlushAll(context.Background()), "failed")
+	assert.ElementsMatch(t, []string{"first", "second"}, []string{<-flushed, <-flushed})
+
+	unregister1()
+	unregister2()
+	assert.NoError(t, FlushAll(context.Background()))
+	assert.Empty(t, flushed)
+}
+
+func TestFlushAndExit(t *testing.T) {
+	flushed := false
+	unregister := RegisterFlusher(FlusherFunc(func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		flushed = true
+		return nil
+	}))
+	defer unregister()
+
+	exitCode := 0
+	flushAndExit(syscall.SIGTERM, time.Second, func(code int) { exitCode = code })
+
+	assert.True(t, flushed)
+	assert.Equal(t, 128+int(syscall.SIGTERM), exitCode)
+}
+
+func TestFlushOnExitStop(t *testing.T) {
+	stop := FlushOnExit(time.Second)
+	stop()
+	stop()
+}
+
+func TestFlushAllStuck(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	unregister := RegisterFlusher(FlusherFunc(func(ctx context.Context) error {
+		<-release // ignores ctx
+		return nil
+	}))
+	defer unregister()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, FlushAll(ctx))
+}
@@ -0,0 +1,33 @@
+package stopwatch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteFoldedStacks writes laps in the folded stacks format used by flamegraph tools:
+// one line per stack with a total time in microseconds, e.g. "fetch;db;query 1234".
+// Dotted states like "fetch.db.query" form the stack, laps with the same state are summed up.
+// Laps of children are included with states prefixed by their names, see Child.
+func (s *Stopwatch) WriteFoldedStacks(w io.Writer) error {
+	s.rlock()
+	laps := append([]Lap(nil), s.treeLaps()...)
+	s.runlock()
+	states, totals := stateTotals(laps)
+	for _, state := range states {
+		if _, err := fmt.Fprintf(w, "%s %d\n", foldedStack(state), totals[state].Microseconds()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldedStack converts a dotted state into frames, frames must not contain the separator
+func foldedStack(state string) string {
+	frames := strings.Split(state, ".")
+	for i, frame := range frames {
+		frames[i] = strings.Replace(frame, ";", "_", -1)
+	}
+	return strings.Join(frames, ";")
+}
@@ -0,0 +1,37 @@
+package stopwatch
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFoldedStacks(t *testing.T) {
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{state: "fetch.db.query", duration: 1200 * time.Microsecond},
+		{state: "fetch.cache", duration: 300 * time.Microsecond},
+		{state: "fetch.db.query", duration: 800 * time.Microsecond},
+		{state: "render;html", duration: 50 * time.Microsecond},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteFoldedStacks(&buf))
+	assert.Equal(t, "fetch;db;query 2000\nfetch;cache 300\nrender_html 50\n", buf.String())
+}
+
+func TestWriteFoldedStacksChildren(t *testing.T) {
+	clock := &fixedClock{now: time.Unix(0, 0)}
+	sw := NewWithClock(0, true, clock)
+	db := sw.Child("db")
+	clock.now = clock.now.Add(time.Millisecond)
+	db.Lap("query")
+	clock.now = clock.now.Add(2 * time.Millisecond)
+	sw.Lap("fetch")
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteFoldedStacks(&buf))
+	assert.Equal(t, "db;query 1000\nfetch 3000\n", buf.String())
+}
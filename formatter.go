@@ -0,0 +1,75 @@
+package stopwatch
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter:
+//
+//	%v  compact summary: running state, elapsed time and number of laps
+//	%+v summary with every lap and its duration
+//	%#v summary with every lap, its offset from the start and data
+//	%s  the same as String(), in the current formatting mode
+//	%q  quoted String()
+func (s *Stopwatch) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			s.writeSummary(f, true, true)
+		case f.Flag('+'):
+			s.writeSummary(f, true, false)
+		default:
+			s.writeSummary(f, false, false)
+		}
+	case 's':
+		io.WriteString(f, s.String())
+	case 'q':
+		io.WriteString(f, strconv.Quote(s.String()))
+	default:
+		fmt.Fprintf(f, "%%!%c(stopwatch)", verb)
+	}
+}
+
+func (s *Stopwatch) writeSummary(w io.Writer, withLaps, withDetails bool) {
+	s.rlock()
+	defer s.runlock()
+
+	status := "stopped"
+	if s.active() {
+		status = "running"
+	}
+	fmt.Fprintf(w, "stopwatch{%s, elapsed %s, %d laps", status, s.ElapsedTime(), len(s.laps))
+
+	if withLaps {
+		for _, lap := range s.laps {
+			fmt.Fprintf(w, ", %s=%s", lap.state, lap.duration)
+			if withDetails {
+				fmt.Fprintf(w, "@+%s", lap.offset)
+				if len(lap.data) > 0 {
+					fmt.Fprintf(w, "%s", formatData(lap.data))
+				}
+			}
+		}
+	}
+	io.WriteString(w, "}")
+}
+
+// formatData renders data with sorted keys, so the output is stable
+func formatData(data map[string]interface{}) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = fmt.Sprintf("%s:%v", k, data[k])
+	}
+	return "{" + strings.Join(items, " ") + "}"
+}
@@ -0,0 +1,29 @@
+package stopwatch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	sw := New(0, false)
+	sw.stop = sw.start.Add(5 * time.Millisecond)
+	sw.laps = []Lap{
+		{formatter: defaultFormatter, state: "parse", duration: time.Millisecond},
+		{formatter: defaultFormatter, state: "db", offset: time.Millisecond, duration: 4 * time.Millisecond, data: map[string]interface{}{"rows": 2, "cache": "miss"}},
+	}
+
+	assert.Equal(t, "stopwatch{stopped, elapsed 5ms, 2 laps}", fmt.Sprintf("%v", sw))
+	assert.Equal(t, "stopwatch{stopped, elapsed 5ms, 2 laps, parse=1ms, db=4ms}", fmt.Sprintf("%+v", sw))
+	assert.Equal(t, "stopwatch{stopped, elapsed 5ms, 2 laps, parse=1ms@+0s, db=4ms@+1ms{cache:miss rows:2}}", fmt.Sprintf("%#v", sw))
+	assert.Equal(t, `[{"state":"parse", "time":"1ms"}, {"state":"db", "time":"4ms", "cache":"miss", "rows":"2"}]`, fmt.Sprintf("%s", sw))
+	assert.Equal(t, "%!d(stopwatch)", fmt.Sprintf("%d", sw))
+}
+
+func TestFormatRunning(t *testing.T) {
+	sw := New(0, true)
+	assert.Contains(t, fmt.Sprint(sw), "stopwatch{running, elapsed ")
+}
@@ -0,0 +1,60 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// googleCloudEntry follows the special fields of Google Cloud Logging structured logs,
+// other fields land in jsonPayload
+type googleCloudEntry struct {
+	Severity      string        `json:"severity"`
+	Message       string        `json:"message"`
+	Trace         string        `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID        string        `json:"logging.googleapis.com/spanId,omitempty"`
+	CorrelationID string        `json:"correlation_id,omitempty"`
+	RunID         string        `json:"run_id,omitempty"`
+	ElapsedMs     float64       `json:"elapsed_ms"`
+	Laps          []DetailedLap `json:"laps"`
+}
+
+// SetGoogleCloudProject sets the project ID used to build trace fields in FormattingModeGoogleCloud,
+// so stopwatch lines correlate with Cloud Trace. Trace and span IDs are taken from the last lap
+// recorded by LapWithContext with a trace.
+func (s *Stopwatch) SetGoogleCloudProject(projectID string) {
+	s.lock()
+	defer s.unlock()
+	s.gcpProject = projectID
+}
+
+// formatGoogleCloud must be called under the read lock
+func (s *Stopwatch) formatGoogleCloud() (string, error) {
+	elapsed := s.ElapsedTime()
+	entry := googleCloudEntry{
+		Severity:      "INFO",
+		Message:       fmt.Sprintf("stopwatch: %d laps in %s", len(s.laps), elapsed),
+		CorrelationID: s.correlationID,
+		RunID:         s.runID,
+		ElapsedMs:     milliseconds(elapsed),
+		Laps:          make([]DetailedLap, len(s.laps)),
+	}
+
+	for i, lap := range s.laps {
+		entry.Laps[i] = newDetailedLap(lap)
+		entry.Laps[i].CorrelationID = ""
+		if lap.runID == s.runID {
+			entry.Laps[i].RunID = ""
+		}
+
+		if traceID, ok := lap.data[TraceIDKey]; ok && s.gcpProject != "" {
+			entry.Trace = fmt.Sprintf("projects/%s/traces/%v", s.gcpProject, traceID)
+			entry.SpanID = fmt.Sprint(lap.data[SpanIDKey])
+		}
+	}
+
+	result, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
@@ -0,0 +1,44 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoogleCloudFormatting(t *testing.T) {
+	sw := New(0, false)
+	sw.stop = sw.start.Add(3 * time.Millisecond)
+	sw.SetFormattingMode(FormattingModeGoogleCloud)
+	sw.SetGoogleCloudProject("my-project")
+	sw.SetCorrelationID("req-1")
+	sw.SetRunID("run-1")
+	sw.laps = []Lap{
+		{state: "db", duration: 2 * time.Millisecond, correlationID: "req-1",
+			data: map[string]interface{}{TraceIDKey: "abc", SpanIDKey: "def"}},
+		{state: "render", offset: 2 * time.Millisecond, duration: time.Millisecond, correlationID: "req-1"},
+	}
+
+	assert.JSONEq(t, `{
+		"severity": "INFO",
+		"message": "stopwatch: 2 laps in 3ms",
+		"logging.googleapis.com/trace": "projects/my-project/traces/abc",
+		"logging.googleapis.com/spanId": "def",
+		"correlation_id": "req-1",
+		"run_id": "run-1",
+		"elapsed_ms": 3,
+		"laps": [
+			{"state": "db", "ms": 2, "offset_ms": 0, "data": {"trace_id": "abc", "span_id": "def"}},
+			{"state": "render", "ms": 1, "offset_ms": 2}
+		]
+	}`, sw.String())
+}
+
+func TestGoogleCloudFormattingWithoutProject(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeGoogleCloud)
+	sw.LapWithData("db", map[string]interface{}{TraceIDKey: "abc"})
+
+	assert.NotContains(t, sw.String(), "logging.googleapis.com/trace")
+}
@@ -0,0 +1,72 @@
+package stopwatch
+
+import (
+	"bytes"
+	"runtime"
+	"time"
+)
+
+const (
+	// GoroutinesKey is the lap data key of goroutine counts by state on slow laps
+	GoroutinesKey = "goroutines"
+	// GoroutineDumpKey is the lap data key of the full goroutine dump on slow laps
+	GoroutineDumpKey = "goroutine_dump"
+)
+
+// SetSlowLapProfile snapshots goroutines when a lap takes longer than threshold, so slow
+// phases caused by lock convoys or stuck channels become visible: lap data gets goroutine
+// counts by state, like {"semacquire": 40, "running": 1}, and with dump the full goroutine
+// dump too. Taking a dump stops the world, so keep the threshold for really slow laps.
+// Zero threshold turns it off.
+func (s *Stopwatch) SetSlowLapProfile(threshold time.Duration, dump bool) {
+	s.lock()
+	defer s.unlock()
+	s.slowLap = threshold
+	s.slowLapDump = dump
+}
+
+// profileSlowLap must be called under the lock
+func (s *Stopwatch) profileSlowLap(duration time.Duration, data map[string]interface{}) map[string]interface{} {
+	if s.slowLap <= 0 || duration <= s.slowLap {
+		return data
+	}
+	stacks := goroutineDump()
+	data = copyData(data, 2)
+	data[GoroutinesKey] = goroutineStates(stacks)
+	if s.slowLapDump {
+		data[GoroutineDumpKey] = string(stacks)
+	}
+	return data
+}
+
+func goroutineDump() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// goroutineStates counts goroutines of a dump by state, found in headers
+// like "goroutine 7 [chan receive, 2 minutes]:"
+func goroutineStates(dump []byte) map[string]int {
+	states := map[string]int{}
+	for _, line := range bytes.Split(dump, []byte{'\n'}) {
+		if !bytes.HasPrefix(line, []byte("goroutine ")) {
+			continue
+		}
+		open, end := bytes.IndexByte(line, '['), bytes.IndexByte(line, ']')
+		if open < 0 || end < open {
+			continue
+		}
+		state := line[open+1 : end]
+		if comma := bytes.IndexByte(state, ','); comma >= 0 {
+			state = state[:comma]
+		}
+		states[string(state)]++
+	}
+	return states
+}
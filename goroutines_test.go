@@ -0,0 +1,44 @@
+package stopwatch
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowLapProfile(t *testing.T) {
+	sw := New(0, false)
+	sw.SetSlowLapProfile(10*time.Millisecond, false)
+
+	sw.stop = sw.start.Add(5 * time.Millisecond)
+	lap := sw.LapWithData("fast", map[string]interface{}{"rows": 2})
+	assert.Equal(t, map[string]interface{}{"rows": 2}, lap.data)
+
+	blocked := make(chan struct{})
+	go func() { <-blocked }()
+	defer close(blocked)
+
+	sw.stop = sw.start.Add(20 * time.Millisecond)
+	lap = sw.LapWithData("slow", map[string]interface{}{"rows": 2})
+	states := lap.data[GoroutinesKey].(map[string]int)
+	assert.True(t, states["running"] >= 1)
+	total := 0
+	for _, count := range states {
+		total += count
+	}
+	assert.True(t, total >= 2, "the blocked goroutine too")
+	assert.NotContains(t, lap.data, GoroutineDumpKey)
+	assert.Equal(t, 2, lap.data["rows"])
+
+	sw.SetSlowLapProfile(10*time.Millisecond, true)
+	sw.stop = sw.start.Add(40 * time.Millisecond)
+	lap = sw.Lap("slow")
+	assert.True(t, strings.HasPrefix(lap.data[GoroutineDumpKey].(string), "goroutine "))
+}
+
+func TestGoroutineStates(t *testing.T) {
+	dump := []byte("goroutine 1 [running]:\nmain.main()\n\ngoroutine 7 [chan receive, 2 minutes]:\n\ngoroutine 8 [chan receive]:\n")
+	assert.Equal(t, map[string]int{"running": 1, "chan receive": 2}, goroutineStates(dump))
+}
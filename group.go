@@ -0,0 +1,107 @@
+package stopwatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TaskResult is the outcome of a TimedGroup task
+type TaskResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// TimedGroup runs tasks in goroutines and waits for them like errgroup.Group does,
+// recording every task as a lap of the stopwatch, with the error in "error" lap data.
+// Task laps overlap, so unlike other laps they don't add up to the elapsed time,
+// and they don't move the start of the current lap. They are logged as EventTask.
+type TimedGroup struct {
+	sw     *Stopwatch
+	cancel func()
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	err     error
+	results []TaskResult
+}
+
+// NewTimedGroup creates a group recording tasks to the stopwatch
+func NewTimedGroup(sw *Stopwatch) *TimedGroup {
+	return &TimedGroup{sw: sw}
+}
+
+// NewTimedGroupWithContext creates a group and a context derived from ctx,
+// canceled when a task fails or Wait returns, like errgroup.WithContext
+func NewTimedGroupWithContext(ctx context.Context, sw *Stopwatch) (*TimedGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &TimedGroup{sw: sw, cancel: cancel}, ctx
+}
+
+// Go runs fn in a goroutine and records it as a lap with the name as the state
+func (g *TimedGroup) Go(name string, fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		start := g.sw.now()
+		err := fn()
+		end := g.sw.now()
+
+		var data map[string]interface{}
+		if err != nil {
+			data = map[string]interface{}{"error": err.Error()}
+		}
+		g.sw.recordTask(name, start, end, data)
+
+		g.mu.Lock()
+		g.results = append(g.results, TaskResult{Name: name, Duration: end.Sub(start), Err: err})
+		first := err != nil && g.err == nil
+		if first {
+			g.err = err
+		}
+		g.mu.Unlock()
+		if first && g.cancel != nil {
+			g.cancel()
+		}
+	}()
+}
+
+// Wait waits for all tasks and returns the first error
+func (g *TimedGroup) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// Results returns outcomes of finished tasks in order of completion,
+// call it after Wait for the report of all tasks
+func (g *TimedGroup) Results() []TaskResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]TaskResult(nil), g.results...)
+}
+
+// recordTask records a lap from start to end without moving the mark
+func (s *Stopwatch) recordTask(state string, start, end time.Time, data map[string]interface{}) {
+	s.lock()
+	if s.disabled {
+		s.unlock()
+		return
+	}
+	offset := s.ElapsedTimeFrom(start).Round(s.resolution)
+	lap, event, ok := s.processLap(EventTask, state, offset, end.Sub(start).Round(s.resolution), end, data)
+	var sinks []Sink
+	var log func(Event)
+	if ok {
+		sinks, log = s.keepLap(lap, end), s.eventLog
+	}
+	s.unlock()
+
+	publishLap(lap, event, sinks, log)
+}
@@ -0,0 +1,83 @@
+package stopwatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimedGroup(t *testing.T) {
+	sw := New(0, true)
+	var mu sync.Mutex
+	var sunk []string
+	sw.AddSink(SinkFunc(func(lap Lap) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sunk = append(sunk, lap.state)
+		return nil
+	}))
+
+	g := NewTimedGroup(sw)
+	g.Go("slow", func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	g.Go("failing", func() error { return errors.New("failed") })
+	assert.EqualError(t, g.Wait(), "failed")
+
+	results := g.Results()
+	assert.Len(t, results, 2)
+	assert.Equal(t, "failing", results[0].Name)
+	assert.EqualError(t, results[0].Err, "failed")
+	assert.Equal(t, "slow", results[1].Name)
+	assert.True(t, results[1].Duration >= 10*time.Millisecond)
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 2)
+	assert.Equal(t, "failed", laps[0].data["error"])
+	assert.Nil(t, laps[1].data)
+	assert.True(t, laps[1].Duration() >= 10*time.Millisecond)
+	assert.Equal(t, []string{"failing", "slow"}, sunk)
+
+	assert.Zero(t, sw.mark, "the current lap is not moved")
+}
+
+func TestTimedGroupWithContext(t *testing.T) {
+	g, ctx := NewTimedGroupWithContext(context.Background(), New(0, true))
+	g.Go("failing", func() error { return errors.New("failed") })
+	g.Go("waiting", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.EqualError(t, g.Wait(), "failed")
+	assert.Len(t, g.Results(), 2)
+}
+
+func TestTimedGroupLapProcessing(t *testing.T) {
+	sw := New(0, true)
+	sw.SetAllowedStates("fetch")
+	var events []Event
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+
+	g := NewTimedGroup(sw)
+	g.Go("unknown", func() error { return nil })
+	assert.NoError(t, g.Wait())
+
+	laps := sw.Laps()
+	if assert.Len(t, laps, 1) {
+		assert.Equal(t, true, laps[0].data[UnexpectedStateKey])
+	}
+	last := events[len(events)-1]
+	assert.Equal(t, EventTask, last.Kind)
+	assert.Equal(t, "unknown", last.State)
+	assert.Equal(t, laps[0].Duration(), last.Delta)
+
+	replayed, err := Replay(events)
+	assert.NoError(t, err)
+	assert.Equal(t, laps[0].Duration(), replayed.Laps()[0].Duration())
+	assert.Equal(t, time.Duration(0), replayed.mark, "tasks don't move the mark")
+}
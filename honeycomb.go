@@ -0,0 +1,278 @@
+package stopwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHoneycombAPIHost is the Honeycomb API used unless configured otherwise
+	DefaultHoneycombAPIHost = "https://api.honeycomb.io"
+	// DefaultHoneycombTimeout limits requests of HoneycombSink without a client
+	DefaultHoneycombTimeout = 10 * time.Second
+
+	// honeycombQueueSize is the number of full batches waiting to be sent, more are dropped
+	honeycombQueueSize = 16
+)
+
+// HoneycombConfig configures HoneycombSink
+type HoneycombConfig struct {
+	// APIKey is sent in the X-Honeycomb-Team header
+	APIKey string
+	// Dataset receives events
+	Dataset string
+	// APIHost is DefaultHoneycombAPIHost if empty
+	APIHost string
+	// BatchSize is the number of events sent in one request, 50 if zero
+	BatchSize int
+	// FlushInterval sends queued events periodically, so they don't wait for a full batch.
+	// Zero sends them on Flush or Close only.
+	FlushInterval time.Duration
+	// Client sends batches, a client with DefaultHoneycombTimeout if nil
+	Client *http.Client
+	// Retry repeats failed requests, no retries by default
+	Retry RetryPolicy
+}
+
+// HoneycombSink sends laps to Honeycomb in batches. As a Sink it sends an event per lap,
+// AddWide adds one wide event per stopwatch with per-state fields instead.
+// Batches are sent from a background goroutine, so a slow Honeycomb doesn't slow down
+// the timed code, full batches are dropped if too many of them wait to be sent.
+// Call Flush to send the rest of events.
+type HoneycombSink struct {
+	cfg     HoneycombConfig
+	mu      sync.Mutex
+	events  []honeycombEvent
+	closed  bool
+	queue   chan honeycombBatch // to the sender goroutine
+	pending int                 // batches queued or being sent
+
+	stop     chan struct{} // stops periodic flushes
+	stopOnce sync.Once
+	done     chan struct{} // stops the sender goroutine
+	doneOnce sync.Once
+}
+
+// honeycombBatch is sent by the sender goroutine, which reports the result to done if it's set
+type honeycombBatch struct {
+	ctx    context.Context
+	events []honeycombEvent
+	done   chan error
+}
+
+type honeycombEvent struct {
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// NewHoneycombSink creates a sink, see HoneycombConfig for defaults
+func NewHoneycombSink(cfg HoneycombConfig) *HoneycombSink {
+	if cfg.APIHost == "" {
+		cfg.APIHost = DefaultHoneycombAPIHost
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: DefaultHoneycombTimeout}
+	}
+	h := &HoneycombSink{
+		cfg:   cfg,
+		queue: make(chan honeycombBatch, honeycombQueueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go h.run()
+	if cfg.FlushInterval > 0 {
+		go flushEvery(cfg.FlushInterval, h.stop, h.Flush)
+	}
+	return h
+}
+
+// WriteLap queues an event with lap name, duration_ms, correlation and run IDs and lap data.
+// A full batch is passed to the sender goroutine right away.
+func (h *HoneycombSink) WriteLap(lap Lap) error {
+	data := copyData(lap.data, 4)
+	data["name"] = lap.state
+	data["duration_ms"] = milliseconds(lap.duration)
+	if lap.correlationID != "" {
+		data[CorrelationIDKey] = lap.correlationID
+	}
+	if lap.runID != "" {
+		data[RunIDKey] = lap.runID
+	}
+	return h.add(honeycombEvent{Time: lap.end.Add(-lap.duration), Data: data})
+}
+
+// AddWide queues a single event for the whole stopwatch: duration_ms, lap_count and
+// "<state>.duration_ms", "<state>.count" for every lap state.
+// A full batch is passed to the sender goroutine right away.
+func (h *HoneycombSink) AddWide(sw *Stopwatch) error {
+	laps := sw.Laps()
+	data := map[string]interface{}{
+		"duration_ms": milliseconds(sw.ElapsedTime()),
+		"lap_count":   len(laps),
+	}
+	if id := sw.correlationIDSnapshot(); id != "" {
+		data[CorrelationIDKey] = id
+	}
+	if id := sw.RunID(); id != "" {
+		data[RunIDKey] = id
+	}
+
+	counts := map[string]int{}
+	for _, lap := range laps {
+		counts[lap.state]++
+	}
+	states, totals := stateTotals(laps)
+	for _, state := range states {
+		data[state+".duration_ms"] = milliseconds(totals[state])
+		data[state+".count"] = counts[state]
+	}
+
+	eventTime := sw.now()
+	if len(laps) > 0 {
+		eventTime = laps[0].end.Add(-laps[0].duration)
+	}
+	return h.add(honeycombEvent{Time: eventTime, Data: data})
+}
+
+func (h *HoneycombSink) add(event honeycombEvent) error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return ErrClosed
+	}
+	h.events = append(h.events, event)
+	var batch []honeycombEvent
+	if len(h.events) >= h.cfg.BatchSize {
+		batch = h.events
+		h.events = nil
+	}
+	h.mu.Unlock()
+
+	if batch == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case h.queue <- honeycombBatch{ctx: context.Background(), events: batch}:
+		h.pending++
+	default:
+		countLapsDropped(len(batch))
+	}
+	return nil
+}
+
+// run sends batches in order until the sink is closed
+func (h *HoneycombSink) run() {
+	for {
+		select {
+		case batch := <-h.queue:
+			err := countExport(h.send(batch.ctx, batch.events))
+			h.mu.Lock()
+			h.pending--
+			h.mu.Unlock()
+			if batch.done != nil {
+				batch.done <- err
+			}
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// unqueue drops events of a flush that didn't get into the queue
+func (h *HoneycombSink) unqueue(events []honeycombEvent) {
+	h.mu.Lock()
+	h.pending--
+	h.mu.Unlock()
+	countLapsDropped(len(events))
+}
+
+// Flush sends all queued events, after full batches waiting to be sent
+func (h *HoneycombSink) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	events := h.events
+	h.events = nil
+	if len(events) == 0 && h.pending == 0 {
+		h.mu.Unlock()
+		return nil
+	}
+	h.pending++
+	h.mu.Unlock()
+
+	// buffered, so the sender never waits for a flush given up
+	batch := honeycombBatch{ctx: ctx, events: events, done: make(chan error, 1)}
+	select {
+	case h.queue <- batch:
+	case <-ctx.Done():
+		h.unqueue(events)
+		return ctx.Err()
+	case <-h.done:
+		h.unqueue(events)
+		return ErrClosed
+	}
+	select {
+	case err := <-batch.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-h.done:
+		return ErrClosed
+	}
+}
+
+// ForceFlush sends all queued events right away, see Flush to limit the time it takes
+func (h *HoneycombSink) ForceFlush() error {
+	return h.Flush(context.Background())
+}
+
+// Close sends all queued events and rejects new ones
+func (h *HoneycombSink) Close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	h.stopOnce.Do(func() { close(h.stop) })
+
+	err := h.Flush(ctx)
+	h.doneOnce.Do(func() { close(h.done) })
+	h.cfg.Client.CloseIdleConnections()
+	return err
+}
+
+func (h *HoneycombSink) send(ctx context.Context, batch []honeycombEvent) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	endpoint := h.cfg.APIHost + "/1/batch/" + url.PathEscape(h.cfg.Dataset)
+	return h.cfg.Retry.do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Honeycomb-Team", h.cfg.APIKey)
+
+		resp, err := h.cfg.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return &StatusError{Service: "honeycomb", StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	})
+}
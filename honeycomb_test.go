@@ -0,0 +1,165 @@
+package stopwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type honeycombServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	batches [][]honeycombEvent
+}
+
+func newHoneycombServer(t *testing.T) *honeycombServer {
+	h := &honeycombServer{}
+	h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/1/batch/timings", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Honeycomb-Team"))
+
+		var batch []honeycombEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		h.mu.Lock()
+		h.batches = append(h.batches, batch)
+		h.mu.Unlock()
+	}))
+	return h
+}
+
+// received returns the number of batches received
+func (h *honeycombServer) received() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.batches)
+}
+
+func TestHoneycombSinkBatches(t *testing.T) {
+	server := newHoneycombServer(t)
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{APIKey: "secret", Dataset: "timings", APIHost: server.URL, BatchSize: 2})
+	sw := New(0, true)
+	sw.AddSink(sink)
+	sw.SetCorrelationID("req-1")
+
+	sw.Lap("lap1")
+	assert.Zero(t, server.received())
+	sw.LapWithData("lap2", map[string]interface{}{"rows": 2})
+	assert.Eventually(t, func() bool { return server.received() == 1 }, time.Second, time.Millisecond)
+	sw.Lap("lap3")
+	assert.NoError(t, sink.Flush(context.Background()))
+	assert.Equal(t, 2, server.received())
+
+	event := server.batches[0][1].Data
+	assert.Equal(t, "lap2", event["name"])
+	assert.Equal(t, 2.0, event["rows"])
+	assert.Equal(t, "req-1", event[CorrelationIDKey])
+	assert.Contains(t, event, "duration_ms")
+
+	assert.NoError(t, sink.Flush(context.Background()), "nothing to flush")
+	assert.Equal(t, 2, server.received())
+}
+
+func TestHoneycombSinkSlow(t *testing.T) {
+	release := make(chan struct{})
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		<-release
+	}))
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{Dataset: "api/timings", APIHost: server.URL, BatchSize: 1})
+	sw := New(0, true)
+	sw.AddSink(sink)
+
+	recorded := make(chan struct{})
+	go func() {
+		sw.Lap("lap1")
+		sw.Lap("lap2")
+		close(recorded)
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Fatal("laps wait for Honeycomb")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, sink.Flush(ctx), "the first batch is still being sent")
+
+	close(release)
+	assert.NoError(t, sink.Close(context.Background()))
+	assert.Equal(t, []string{"/1/batch/api%2Ftimings", "/1/batch/api%2Ftimings"}, paths)
+}
+
+func TestHoneycombSinkWide(t *testing.T) {
+	server := newHoneycombServer(t)
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{APIKey: "secret", Dataset: "timings", APIHost: server.URL})
+	sw := New(0, true)
+	sw.Lap("db")
+	sw.Lap("render")
+	sw.Lap("db")
+
+	assert.NoError(t, sink.AddWide(sw))
+	assert.NoError(t, sink.Flush(context.Background()))
+
+	assert.Len(t, server.batches, 1)
+	event := server.batches[0][0].Data
+	assert.Equal(t, 3.0, event["lap_count"])
+	assert.Equal(t, 2.0, event["db.count"])
+	assert.Equal(t, 1.0, event["render.count"])
+	assert.Contains(t, event, "db.duration_ms")
+
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	assert.NoError(t, sink.AddWide(NewWithClock(0, true, clock)))
+	assert.NoError(t, sink.Flush(context.Background()))
+	if assert.Equal(t, 2, server.received()) {
+		assert.True(t, clock.now.Equal(server.batches[1][0].Time), "no laps, the time of the stopwatch clock")
+	}
+}
+
+func TestHoneycombSinkClose(t *testing.T) {
+	server := newHoneycombServer(t)
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{APIKey: "secret", Dataset: "timings", APIHost: server.URL})
+	sw := New(0, true)
+	sw.AddSink(sink)
+	sw.Lap("lap1")
+
+	assert.NoError(t, sw.Close(context.Background()))
+	assert.Equal(t, 1, server.received())
+	assert.Equal(t, ErrClosed, sink.WriteLap(Lap{state: "lap2"}))
+}
+
+func TestHoneycombSinkFlushInterval(t *testing.T) {
+	server := newHoneycombServer(t)
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{APIKey: "secret", Dataset: "timings", APIHost: server.URL, FlushInterval: time.Millisecond})
+	defer sink.Close(context.Background())
+	assert.NoError(t, sink.WriteLap(Lap{state: "lap1"}))
+
+	assert.Eventually(t, func() bool { return server.received() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestHoneycombSinkForceFlush(t *testing.T) {
+	server := newHoneycombServer(t)
+	defer server.Close()
+
+	sink := NewHoneycombSink(HoneycombConfig{APIKey: "secret", Dataset: "timings", APIHost: server.URL})
+	assert.NoError(t, sink.WriteLap(Lap{state: "lap1"}))
+	assert.NoError(t, sink.ForceFlush())
+	assert.Equal(t, 1, server.received())
+}
@@ -0,0 +1,203 @@
+package stopwatch
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale is a translation table for HumanFormatter
+type Locale struct {
+	// Units are names of hours, minutes, seconds, milliseconds, microseconds and nanoseconds.
+	// Every unit lists its plural forms, the form is chosen by Plural.
+	Hours, Minutes, Seconds, Milliseconds, Microseconds, Nanoseconds []string
+	// Plural returns the index of the plural form for the number
+	Plural func(n int64) int
+	// Separator is put between units, e.g. " " or ", "
+	Separator string
+}
+
+var (
+	// LocaleEnglish renders "1 minute 5 seconds"
+	LocaleEnglish = Locale{
+		Hours:        []string{"hour", "hours"},
+		Minutes:      []string{"minute", "minutes"},
+		Seconds:      []string{"second", "seconds"},
+		Milliseconds: []string{"millisecond", "milliseconds"},
+		Microseconds: []string{"microsecond", "microseconds"},
+		Nanoseconds:  []string{"nanosecond", "nanoseconds"},
+		Plural:       pluralEnglish,
+		Separator:    " ",
+	}
+
+	// LocaleGerman renders "1 Minute 5 Sekunden"
+	LocaleGerman = Locale{
+		Hours:        []string{"Stunde", "Stunden"},
+		Minutes:      []string{"Minute", "Minuten"},
+		Seconds:      []string{"Sekunde", "Sekunden"},
+		Milliseconds: []string{"Millisekunde", "Millisekunden"},
+		Microseconds: []string{"Mikrosekunde", "Mikrosekunden"},
+		Nanoseconds:  []string{"Nanosekunde", "Nanosekunden"},
+		Plural:       pluralEnglish,
+		Separator:    " ",
+	}
+
+	// LocaleRussian renders "1 минута 5 секунд"
+	LocaleRussian = Locale{
+		Hours:        []string{"час", "часа", "часов"},
+		Minutes:      []string{"минута", "минуты", "минут"},
+		Seconds:      []string{"секунда", "секунды", "секунд"},
+		Milliseconds: []string{"миллисекунда", "миллисекунды", "миллисекунд"},
+		Microseconds: []string{"микросекунда", "микросекунды", "микросекунд"},
+		Nanoseconds:  []string{"наносекунда", "наносекунды", "наносекунд"},
+		Plural:       pluralRussian,
+		Separator:    " ",
+	}
+)
+
+func pluralEnglish(n int64) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+func pluralRussian(n int64) int {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// humanUnitsShown is how many of the most significant units HumanFormatter renders
+const humanUnitsShown = 2
+
+// HumanFormatter returns a formatter rendering durations with words of the locale,
+// e.g. "1 minute 5 seconds". Only the two most significant units are shown.
+func HumanFormatter(locale Locale) func(time.Duration) string {
+	units := []struct {
+		size  time.Duration
+		names []string
+	}{
+		{time.Hour, locale.Hours},
+		{time.Minute, locale.Minutes},
+		{time.Second, locale.Seconds},
+		{time.Millisecond, locale.Milliseconds},
+		{time.Microsecond, locale.Microseconds},
+		{time.Nanosecond, locale.Nanoseconds},
+	}
+
+	return func(duration time.Duration) string {
+		prefix := ""
+		if duration < 0 {
+			prefix = "-"
+			duration = -duration
+		}
+
+		var parts []string
+		for _, unit := range units {
+			if len(parts) == humanUnitsShown {
+				break
+			}
+			n := int64(duration / unit.size)
+			duration -= time.Duration(n) * unit.size
+			if n == 0 && len(parts) == 0 {
+				continue // skip leading zero units
+			}
+			if n == 0 {
+				break // "1 hour 0 minutes" says nothing
+			}
+			parts = append(parts, strconv.FormatInt(n, 10)+" "+pluralForm(locale, unit.names, n))
+		}
+
+		if len(parts) == 0 {
+			return "0 " + pluralForm(locale, locale.Seconds, 0)
+		}
+		return prefix + strings.Join(parts, locale.Separator)
+	}
+}
+
+func pluralForm(locale Locale, names []string, n int64) string {
+	form := 0
+	if locale.Plural != nil {
+		form = locale.Plural(n)
+	}
+	if form >= len(names) {
+		form = len(names) - 1
+	}
+	return names[form]
+}
+
+// CompactFormatter returns a formatter rendering durations in a single unit with limited
+// significant digits, e.g. "1.2s", "430ms" or "18µs" for 2 significant digits.
+// Integer digits are never dropped.
+func CompactFormatter(significant int) func(time.Duration) string {
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+		{time.Millisecond, "ms"},
+		{time.Microsecond, "µs"},
+		{time.Nanosecond, "ns"},
+	}
+
+	return func(duration time.Duration) string {
+		prefix := ""
+		if duration < 0 {
+			prefix = "-"
+			duration = -duration
+		}
+
+		for _, unit := range units {
+			if duration < unit.size && unit.size != time.Nanosecond {
+				continue
+			}
+			value := float64(duration) / float64(unit.size)
+			decimals := significant - len(strconv.FormatInt(int64(value), 10))
+			if decimals < 0 || unit.size == time.Nanosecond {
+				decimals = 0
+			}
+			formatted := strconv.FormatFloat(value, 'f', decimals, 64)
+			if strings.Contains(formatted, ".") {
+				formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+			}
+			return prefix + formatted + unit.name
+		}
+		return "0s" // unreachable, nanoseconds match everything
+	}
+}
+
+// AdaptiveUnit picks the unit for a duration by its magnitude: ns, µs, ms or s,
+// and returns the duration in that unit
+func AdaptiveUnit(duration time.Duration) (float64, string) {
+	magnitude := duration
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	switch {
+	case magnitude >= time.Second:
+		return duration.Seconds(), "s"
+	case magnitude >= time.Millisecond:
+		return float64(duration) / float64(time.Millisecond), "ms"
+	case magnitude >= time.Microsecond:
+		return float64(duration) / float64(time.Microsecond), "µs"
+	default:
+		return float64(duration), "ns"
+	}
+}
+
+// AdaptiveFormatter returns a formatter picking the unit per lap, see AdaptiveUnit,
+// with a fixed number of decimal places, e.g. "3.00ns" or "2700.00s" for 2 decimals
+func AdaptiveFormatter(decimals int) func(time.Duration) string {
+	return func(duration time.Duration) string {
+		value, unit := AdaptiveUnit(duration)
+		return strconv.FormatFloat(value, 'f', decimals, 64) + unit
+	}
+}
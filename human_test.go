@@ -0,0 +1,62 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanFormatter(t *testing.T) {
+	format := HumanFormatter(LocaleEnglish)
+
+	assert.Equal(t, "0 seconds", format(0))
+	assert.Equal(t, "1 nanosecond", format(1))
+	assert.Equal(t, "1 minute 5 seconds", format(65*time.Second+300*time.Millisecond))
+	assert.Equal(t, "2 hours", format(2*time.Hour+30*time.Second))
+	assert.Equal(t, "-15 milliseconds", format(-15*time.Millisecond))
+}
+
+func TestHumanFormatterRussian(t *testing.T) {
+	format := HumanFormatter(LocaleRussian)
+
+	assert.Equal(t, "1 минута 5 секунд", format(65*time.Second))
+	assert.Equal(t, "2 часа 21 минута", format(2*time.Hour+21*time.Minute))
+	assert.Equal(t, "11 секунд", format(11*time.Second))
+}
+
+func TestHumanFormatterCustomLocale(t *testing.T) {
+	locale := LocaleGerman
+	locale.Separator = ", "
+
+	sw := New(0, false)
+	sw.SetFormatter(HumanFormatter(locale))
+	sw.LapWithDataAndTime(time.Now().Add(61*time.Second), "lap", nil)
+
+	assert.Equal(t, "1 Minute, 1 Sekunde", sw.Laps()[0].formatter(61*time.Second))
+}
+
+func TestCompactFormatter(t *testing.T) {
+	format := CompactFormatter(2)
+
+	assert.Equal(t, "0ns", format(0))
+	assert.Equal(t, "1.2s", format(1234567*time.Microsecond))
+	assert.Equal(t, "430ms", format(430467*time.Microsecond))
+	assert.Equal(t, "18µs", format(18200*time.Nanosecond))
+	assert.Equal(t, "1s", format(time.Second+time.Millisecond))
+	assert.Equal(t, "2.5m", format(150*time.Second))
+	assert.Equal(t, "-3.1h", format(-3*time.Hour-7*time.Minute))
+	assert.Equal(t, "999ns", format(999))
+
+	assert.Equal(t, "1.235s", CompactFormatter(4)(1234567*time.Microsecond))
+}
+
+func TestAdaptiveFormatter(t *testing.T) {
+	format := AdaptiveFormatter(1)
+
+	assert.Equal(t, "3.0ns", format(3))
+	assert.Equal(t, "1.5µs", format(1500))
+	assert.Equal(t, "12.3ms", format(12300*time.Microsecond))
+	assert.Equal(t, "2700.0s", format(45*time.Minute))
+	assert.Equal(t, "-2.0ms", format(-2*time.Millisecond))
+}
@@ -0,0 +1,52 @@
+package stopwatch
+
+import (
+	"sort"
+	"time"
+)
+
+// IdleState is the state of the synthetic laps returned by Idle
+const IdleState = "idle"
+
+// Idle returns the periods the stopwatch ran while no kept lap was open, as synthetic laps
+// of IdleState, in the order of their offsets. Laps don't always follow each other:
+// MeasureRetry and MeasureWithTimeout don't count the time before their calls and the backoff,
+// while tasks of a TimedGroup and MultiLap overlap, so overlapping laps count as one.
+// The time since the end of the last lap is idle as well. Pauses aren't idle,
+// the stopwatch doesn't run while paused.
+func (s *Stopwatch) Idle() []Lap {
+	s.rlock()
+	defer s.runlock()
+
+	// laps recorded with past times, group tasks and adjustments break the order of offsets
+	laps := make([]Lap, len(s.laps))
+	copy(laps, s.laps)
+	sort.SliceStable(laps, func(i, j int) bool { return laps[i].offset < laps[j].offset })
+
+	var idle []Lap
+	var covered time.Duration // end of the laps so far
+	for _, lap := range laps {
+		if lap.offset > covered {
+			idle = append(idle, s.idleLap(covered, lap.offset))
+		}
+		if end := lap.offset + lap.duration; end > covered {
+			covered = end
+		}
+	}
+	if elapsed := s.ElapsedTime().Round(s.resolution); elapsed > covered {
+		idle = append(idle, s.idleLap(covered, elapsed))
+	}
+	return idle
+}
+
+// idleLap must be called under the read lock
+func (s *Stopwatch) idleLap(from, to time.Duration) Lap {
+	return Lap{
+		formatter:     s.formatter,
+		state:         IdleState,
+		offset:        from,
+		duration:      to - from,
+		correlationID: s.correlationID,
+		runID:         s.runID,
+	}
+}
@@ -0,0 +1,38 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdle(t *testing.T) {
+	clock := &fixedClock{now: time.Unix(100, 0)}
+	sw := NewWithClock(0, true, clock)
+	assert.Empty(t, sw.Idle())
+
+	clock.now = clock.now.Add(time.Second)
+	sw.Lap("parse")
+	clock.now = clock.now.Add(2 * time.Second)
+	sw.markLapStart()
+	clock.now = clock.now.Add(time.Second)
+	sw.Lap("db")
+	// a task overlapping db, finished later
+	sw.laps = append(sw.laps, NewLap("task", 3500*time.Millisecond, 2*time.Second, nil))
+	clock.now = clock.now.Add(3 * time.Second)
+
+	idle := sw.Idle()
+	if assert.Len(t, idle, 2) {
+		assert.Equal(t, IdleState, idle[0].State())
+		assert.Equal(t, time.Second, idle[0].StartOffset())
+		assert.Equal(t, 2*time.Second, idle[0].Duration())
+		assert.Equal(t, 5500*time.Millisecond, idle[1].StartOffset())
+		assert.Equal(t, 1500*time.Millisecond, idle[1].Duration())
+	}
+
+	sw.Pause("lunch")
+	clock.now = clock.now.Add(time.Hour)
+	assert.Len(t, sw.Idle(), 2, "pauses aren't idle")
+	assert.Len(t, sw.Laps(), 3, "idle laps aren't recorded")
+}
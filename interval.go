@@ -0,0 +1,107 @@
+package stopwatch
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is a period of time the stopwatch was running
+type Interval struct {
+	Start, End time.Time
+}
+
+// Duration returns the length of the interval
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// ActiveIntervals returns periods the stopwatch was running since New or Reset, the last one
+// ends now if it is running. Offsets of New and Reset count as running before the start.
+func (s *Stopwatch) ActiveIntervals() []Interval {
+	s.rlock()
+	defer s.runlock()
+	intervals := make([]Interval, len(s.intervals), len(s.intervals)+1)
+	copy(intervals, s.intervals)
+	if s.active() {
+		intervals = append(intervals, Interval{Start: s.activeSince, End: s.now()})
+	}
+	return intervals
+}
+
+// Union returns periods any of the stopwatches was running, e.g. to learn how long
+// at least one of the phases was busy
+func Union(stopwatches ...*Stopwatch) []Interval {
+	var all []Interval
+	for _, sw := range stopwatches {
+		all = append(all, sw.ActiveIntervals()...)
+	}
+	return mergeIntervals(all)
+}
+
+// Intersection returns periods all of the stopwatches were running at once, e.g. to learn
+// how long the DB phase and the cache phase ran simultaneously
+func Intersection(stopwatches ...*Stopwatch) []Interval {
+	if len(stopwatches) == 0 {
+		return nil
+	}
+	result := mergeIntervals(stopwatches[0].ActiveIntervals())
+	for _, sw := range stopwatches[1:] {
+		result = intersectIntervals(result, mergeIntervals(sw.ActiveIntervals()))
+	}
+	return result
+}
+
+// TotalDuration sums up durations of the intervals
+func TotalDuration(intervals []Interval) time.Duration {
+	var total time.Duration
+	for _, interval := range intervals {
+		total += interval.Duration()
+	}
+	return total
+}
+
+// mergeIntervals sorts intervals and merges overlapping ones, empty intervals are dropped
+func mergeIntervals(intervals []Interval) []Interval {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.End.After(interval.Start) {
+			sorted = append(sorted, interval)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var result []Interval
+	for _, interval := range sorted {
+		if n := len(result); n > 0 && !interval.Start.After(result[n-1].End) {
+			if interval.End.After(result[n-1].End) {
+				result[n-1].End = interval.End
+			}
+			continue
+		}
+		result = append(result, interval)
+	}
+	return result
+}
+
+// intersectIntervals intersects sorted lists of disjoint intervals
+func intersectIntervals(a, b []Interval) []Interval {
+	var result []Interval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if end.After(start) {
+			result = append(result, Interval{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveIntervals(t *testing.T) {
+	clock := &fixedClock{now: time.Unix(0, 0)}
+	at := func(s int64) time.Time { return time.Unix(s, 0) }
+
+	db := NewWithClock(0, true, clock) // runs 0-10, 20-now
+	cache := NewWithClock(0, false, clock)
+	clock.now = at(5)
+	cache.Start() // runs 5-25
+	clock.now = at(10)
+	db.Stop()
+	db.Stop()
+	clock.now = at(20)
+	db.Start()
+	clock.now = at(25)
+	cache.Stop()
+	clock.now = at(30)
+
+	assert.Equal(t, []Interval{{at(0), at(10)}, {at(20), at(30)}}, db.ActiveIntervals())
+	assert.Equal(t, []Interval{{at(5), at(25)}}, cache.ActiveIntervals())
+
+	union := Union(db, cache)
+	assert.Equal(t, []Interval{{at(0), at(30)}}, union)
+	assert.Equal(t, 30*time.Second, TotalDuration(union))
+
+	both := Intersection(db, cache)
+	assert.Equal(t, []Interval{{at(5), at(10)}, {at(20), at(25)}}, both)
+	assert.Equal(t, 10*time.Second, TotalDuration(both))
+
+	assert.Nil(t, Intersection())
+	db.Reset(0, false)
+	assert.Empty(t, db.ActiveIntervals())
+	assert.Nil(t, Intersection(db, cache))
+}
+
+func TestMergeIntervals(t *testing.T) {
+	at := func(s int64) time.Time { return time.Unix(s, 0) }
+	assert.Equal(t,
+		[]Interval{{at(0), at(4)}, {at(5), at(6)}},
+		mergeIntervals([]Interval{{at(5), at(6)}, {at(2), at(4)}, {at(0), at(3)}, {at(7), at(7)}, {at(1), at(2)}}))
+}
@@ -0,0 +1,57 @@
+package stopwatch
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SetKeyNaming makes keys of the JSON object modes from lap states with the naming function,
+// so output matches existing index mappings. Nil uses states as they are.
+//
+//	sw.SetKeyNaming(func(state string) string { return "timing." + stopwatch.SnakeCase(state) + "_ms" })
+func (s *Stopwatch) SetKeyNaming(naming func(state string) string) {
+	s.lock()
+	defer s.unlock()
+	s.keyNaming = naming
+}
+
+// objectKey must be called under the read lock
+func (s *Stopwatch) objectKey(state string) string {
+	if s.keyNaming == nil {
+		return state
+	}
+	return s.keyNaming(state)
+}
+
+// SnakeCase converts a state like "loadUsers" or "Load Users" to "load_users"
+func SnakeCase(state string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range state {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.':
+			b.WriteRune(r)
+		default:
+			if prev != '_' && i > 0 {
+				b.WriteByte('_')
+			}
+			r = '_'
+		}
+		prev = r
+	}
+	return strings.TrimRight(b.String(), "_")
+}
+
+// KeyFormat returns a naming function putting the state into a fmt format,
+// e.g. KeyFormat("timing.%s_ms")
+func KeyFormat(format string) func(state string) string {
+	return func(state string) string {
+		return fmt.Sprintf(format, state)
+	}
+}
@@ -0,0 +1,33 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetKeyNaming(t *testing.T) {
+	sw := New(0, true)
+	sw.laps = []Lap{{formatter: defaultFormatter, state: "loadUsers", duration: 10 * time.Millisecond}}
+	sw.SetFormattingMode(FormattingModeJsonMsObject)
+
+	sw.SetKeyNaming(func(state string) string { return "timing." + SnakeCase(state) + "_ms" })
+	assert.Equal(t, `{"timing.load_users_ms":10.000}`, sw.String())
+
+	sw.SetKeyNaming(KeyFormat("t_%s"))
+	sw.SetFormattingMode(FormattingModeJsonIntObject)
+	assert.Equal(t, `{"t_loadUsers":10000}`, sw.String())
+
+	sw.SetKeyNaming(nil)
+	sw.SetFormattingMode(FormattingModeJsonSimpleObject)
+	assert.Equal(t, `{"loadUsers":"10ms"}`, sw.String())
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "load_users", SnakeCase("loadUsers"))
+	assert.Equal(t, "load_users", SnakeCase("Load Users"))
+	assert.Equal(t, "db.query_2", SnakeCase("db.query-2"))
+	assert.Equal(t, "step1_done", SnakeCase("step1Done"))
+	assert.Equal(t, "trailing", SnakeCase("trailing!"))
+}
@@ -2,6 +2,7 @@ package stopwatch
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -10,18 +11,92 @@ import (
 type Lap struct {
 	formatter func(time.Duration) string
 	state     string
+	offset    time.Duration // time from the stopwatch start to the lap start
+	end       time.Time     // moment the lap was recorded
 	duration  time.Duration
 	data      map[string]interface{}
+	// correlationID of the stopwatch at the moment the lap was recorded
+	correlationID string
+	seq           uint64 // number of the lap since the stopwatch start, from 1
+	runID         string
+}
+
+// NewLap creates a lap, e.g. to feed laps of other sources into sinks or to compare
+// recorded laps with expected ones. It is formatted with the default formatter.
+func NewLap(state string, startOffset, duration time.Duration, data map[string]interface{}) Lap {
+	return Lap{
+		formatter: defaultFormatter,
+		state:     state,
+		offset:    startOffset,
+		duration:  duration,
+		data:      data,
+	}
+}
+
+// Seq returns the sequence number of the lap: laps are numbered from 1 since New or Reset,
+// not kept laps included, so consumers of streamed laps can order them and drop duplicates
+func (l Lap) Seq() uint64 {
+	return l.seq
+}
+
+// RunID returns the run ID the stopwatch had when the lap was recorded, see SetRunID
+func (l Lap) RunID() string {
+	return l.runID
+}
+
+// CorrelationID returns the correlation ID the stopwatch had when the lap was recorded
+func (l Lap) CorrelationID() string {
+	return l.correlationID
+}
+
+// State returns the name of the lap
+func (l Lap) State() string {
+	return l.state
+}
+
+// Duration returns the length of the lap
+func (l Lap) Duration() time.Duration {
+	return l.duration
+}
+
+// StartOffset returns the time from the stopwatch start to the lap start
+func (l Lap) StartOffset() time.Duration {
+	return l.offset
+}
+
+// Data returns a copy of the lap data, nil if there is none
+func (l Lap) Data() map[string]interface{} {
+	if l.data == nil {
+		return nil
+	}
+	return copyData(l.data, 0)
+}
+
+// formattedDuration formats the duration with the formatter of the stopwatch,
+// or with the default one for laps not recorded by a stopwatch, like the zero Lap
+func (l Lap) formattedDuration() string {
+	if l.formatter == nil {
+		return defaultFormatter(l.duration)
+	}
+	return l.formatter(l.duration)
 }
 
 func (l Lap) String() string {
-	results := fmt.Sprintf(`"state":"%s", "time":"%s"`, l.state, l.formatter(l.duration))
+	results := fmt.Sprintf(`"state":%s, "time":%s`, jsonString(l.state), jsonString(l.formattedDuration()))
+	if l.correlationID != "" {
+		results += fmt.Sprintf(`, %s:%s`, jsonString(CorrelationIDKey), jsonString(l.correlationID))
+	}
 
-	// If lap contains some data, let's merge it
+	// If lap contains some data, let's merge it, sorted by key so the output is stable
 	if len(l.data) > 0 {
-		items := make([]string, 0)
-		for k, v := range l.data {
-			items = append(items, fmt.Sprintf(`"%s":"%s"`, k, v))
+		keys := make([]string, 0, len(l.data))
+		for k := range l.data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, jsonString(k)+":"+jsonString(fmt.Sprint(l.data[k])))
 		}
 		return fmt.Sprintf("{%s, %s}", results, strings.Join(items, ", "))
 	}
@@ -0,0 +1,32 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLap(t *testing.T) {
+	lap := NewLap("db", time.Second, 10*time.Millisecond, map[string]interface{}{"rows": 2})
+
+	assert.Equal(t, "db", lap.State())
+	assert.Equal(t, time.Second, lap.StartOffset())
+	assert.Equal(t, 10*time.Millisecond, lap.Duration())
+	assert.Equal(t, map[string]interface{}{"rows": 2}, lap.Data())
+	assert.Equal(t, `{"state":"db", "time":"10ms", "rows":"2"}`, lap.String())
+
+	lap.Data()["rows"] = 3
+	assert.Equal(t, 2, lap.Data()["rows"], "data can't be modified through Data")
+
+	assert.Nil(t, NewLap("db", 0, 0, nil).Data())
+}
+
+func TestLapAccessors(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("first")
+	lap := sw.LapWithData("second", map[string]interface{}{"rows": 2})
+
+	assert.Equal(t, sw.Laps()[0].Duration(), lap.StartOffset())
+	assert.Equal(t, map[string]interface{}{"rows": 2}, lap.Data())
+}
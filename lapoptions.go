@@ -0,0 +1,57 @@
+package stopwatch
+
+import "time"
+
+// LapOption tunes a lap recorded by Lap
+type LapOption func(*lapOptions)
+
+type lapOptions struct {
+	now   time.Time
+	data  map[string]interface{}
+	owned bool // data is a copy made by options, so it may be modified
+}
+
+// set adds a data entry without modifying maps passed by the caller
+func (o *lapOptions) set(key string, value interface{}) {
+	if !o.owned {
+		o.data = copyData(o.data, 1)
+		o.owned = true
+	}
+	o.data[key] = value
+}
+
+// WithData adds the data to the lap, like LapWithData. Several WithData options are merged.
+func WithData(data map[string]interface{}) LapOption {
+	return func(o *lapOptions) {
+		if o.data == nil {
+			o.data = data
+			return
+		}
+		for k, v := range data {
+			o.set(k, v)
+		}
+	}
+}
+
+// WithTime ends the lap at the given time instead of now, like LapWithDataAndTime
+func WithTime(t time.Time) LapOption {
+	return func(o *lapOptions) {
+		o.now = t
+	}
+}
+
+// WithTag adds a string entry to the lap data
+func WithTag(key, value string) LapOption {
+	return func(o *lapOptions) {
+		o.set(key, value)
+	}
+}
+
+// WithError adds the error message to the lap data under the "error" key, if err is not nil
+func WithError(err error) LapOption {
+	return func(o *lapOptions) {
+		if err != nil {
+			o.set("error", err.Error())
+		}
+	}
+}
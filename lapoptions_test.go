@@ -0,0 +1,31 @@
+package stopwatch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLapOptions(t *testing.T) {
+	sw := New(0, true)
+	data := map[string]interface{}{"rows": 2}
+	at := sw.start.Add(time.Second)
+
+	lap := sw.Lap("db",
+		WithData(data),
+		WithTime(at),
+		WithTag("shard", "3"),
+		WithError(errors.New("timeout")),
+		WithData(map[string]interface{}{"table": "users"}),
+	)
+
+	assert.Equal(t, map[string]interface{}{"rows": 2, "shard": "3", "error": "timeout", "table": "users"}, lap.data)
+	assert.Equal(t, map[string]interface{}{"rows": 2}, data, "caller's data is not modified")
+	assert.Equal(t, at, lap.end)
+	assert.Equal(t, time.Second, lap.Duration())
+
+	lap = sw.Lap("cache", WithData(data), WithError(nil))
+	assert.Equal(t, data, lap.data)
+}
@@ -0,0 +1,79 @@
+package stopwatch
+
+import (
+	"runtime"
+)
+
+const (
+	// BlockEventsKey and BlockCyclesKey are lap data keys of block profile deltas
+	BlockEventsKey = "block_events"
+	BlockCyclesKey = "block_cycles"
+	// MutexEventsKey and MutexCyclesKey are lap data keys of mutex profile deltas
+	MutexEventsKey = "mutex_events"
+	MutexCyclesKey = "mutex_cycles"
+)
+
+// lockTotals are sums of block and mutex profile records
+type lockTotals struct {
+	blockEvents, blockCycles int64
+	mutexEvents, mutexCycles int64
+}
+
+// SetLockProfile adds block and mutex profile deltas to lap data: the number of
+// blocking events and CPU cycles spent waiting during the lap, separating waiting on locks
+// and channels from doing work. Profiles are process-wide, so waits of other goroutines
+// count too. They must be turned on with runtime.SetBlockProfileRate and
+// runtime.SetMutexProfileFraction, the stopwatch doesn't change them.
+func (s *Stopwatch) SetLockProfile(enabled bool) {
+	var totals lockTotals
+	if enabled {
+		totals = readLockTotals()
+	}
+
+	s.lock()
+	defer s.unlock()
+	s.lockProfile = enabled
+	s.lockTotals = totals
+}
+
+// addLockProfile must be called under the lock
+func (s *Stopwatch) addLockProfile(data map[string]interface{}) map[string]interface{} {
+	if !s.lockProfile {
+		return data
+	}
+	totals := readLockTotals()
+	last := s.lockTotals
+	s.lockTotals = totals
+
+	data = copyData(data, 4)
+	data[BlockEventsKey] = totals.blockEvents - last.blockEvents
+	data[BlockCyclesKey] = totals.blockCycles - last.blockCycles
+	data[MutexEventsKey] = totals.mutexEvents - last.mutexEvents
+	data[MutexCyclesKey] = totals.mutexCycles - last.mutexCycles
+	return data
+}
+
+func readLockTotals() lockTotals {
+	var totals lockTotals
+	for _, r := range blockRecords(runtime.BlockProfile) {
+		totals.blockEvents += r.Count
+		totals.blockCycles += r.Cycles
+	}
+	for _, r := range blockRecords(runtime.MutexProfile) {
+		totals.mutexEvents += r.Count
+		totals.mutexCycles += r.Cycles
+	}
+	return totals
+}
+
+// blockRecords reads a profile, growing the slice until all records fit
+func blockRecords(profile func([]runtime.BlockProfileRecord) (int, bool)) []runtime.BlockProfileRecord {
+	n, _ := profile(nil)
+	for {
+		records := make([]runtime.BlockProfileRecord, n+16)
+		var ok bool
+		if n, ok = profile(records); ok {
+			return records[:n]
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package stopwatch
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockProfile(t *testing.T) {
+	runtime.SetBlockProfileRate(1)
+	defer runtime.SetBlockProfileRate(0)
+
+	sw := New(0, true)
+	sw.SetLockProfile(true)
+
+	var mu sync.Mutex
+	mu.Lock()
+	wait := make(chan struct{})
+	go func() {
+		close(wait)
+		mu.Lock() // blocks until unlocked below
+		mu.Unlock()
+	}()
+	<-wait
+	time.Sleep(10 * time.Millisecond)
+	mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	lap := sw.LapWithData("contended", map[string]interface{}{"rows": 2})
+	assert.True(t, lap.data[BlockEventsKey].(int64) >= 1)
+	assert.True(t, lap.data[BlockCyclesKey].(int64) > 0)
+	assert.Contains(t, lap.data, MutexEventsKey)
+	assert.Equal(t, 2, lap.data["rows"])
+
+	sw.SetLockProfile(false)
+	assert.Nil(t, sw.Lap("off").data)
+}
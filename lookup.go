@@ -0,0 +1,75 @@
+package stopwatch
+
+import (
+	"sort"
+	"time"
+)
+
+// FirstLap returns the first kept lap, false if there are none
+func (s *Stopwatch) FirstLap() (Lap, bool) {
+	s.rlock()
+	defer s.runlock()
+	if len(s.laps) == 0 {
+		return Lap{}, false
+	}
+	return s.laps[0], true
+}
+
+// LastLap returns the last kept lap, false if there are none
+func (s *Stopwatch) LastLap() (Lap, bool) {
+	s.rlock()
+	defer s.runlock()
+	if len(s.laps) == 0 {
+		return Lap{}, false
+	}
+	return s.laps[len(s.laps)-1], true
+}
+
+// LapByState returns the last kept lap with the state, false if there are none.
+// Laps are indexed by state as they are recorded, so it's cheap for large stopwatches.
+func (s *Stopwatch) LapByState(state string) (Lap, bool) {
+	s.rlock()
+	defer s.runlock()
+
+	if seq, found := s.lastByState[state]; found {
+		// laps are kept in the order of their sequence numbers
+		i := sort.Search(len(s.laps), func(i int) bool { return s.laps[i].seq >= seq })
+		if i < len(s.laps) && s.laps[i].seq == seq && s.laps[i].state == state {
+			return s.laps[i], true
+		}
+	}
+
+	// laps loaded from files, or evicted since
+	for i := len(s.laps) - 1; i >= 0; i-- {
+		if s.laps[i].state == state {
+			return s.laps[i], true
+		}
+	}
+	return Lap{}, false
+}
+
+// indexLap is called under the lock for every appended lap
+func (s *Stopwatch) indexLap(lap Lap) {
+	if s.lastByState == nil {
+		s.lastByState = make(map[string]uint64)
+	}
+	s.lastByState[lap.state] = lap.seq
+}
+
+// LapsBetween returns kept laps started in the window [from, to) of the run,
+// offsets being measured from the stopwatch start, e.g. the 3rd minute of a job is
+//
+//	sw.LapsBetween(2*time.Minute, 3*time.Minute)
+func (s *Stopwatch) LapsBetween(from, to time.Duration) []Lap {
+	s.rlock()
+	defer s.runlock()
+
+	// laps recorded with past times, group tasks and adjustments break the order of offsets
+	var laps []Lap
+	for _, lap := range s.laps {
+		if lap.offset >= from && lap.offset < to {
+			laps = append(laps, lap)
+		}
+	}
+	return laps
+}
@@ -0,0 +1,81 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLapLookups(t *testing.T) {
+	sw := New(0, true)
+	first, ok := sw.FirstLap()
+	assert.False(t, ok)
+	assert.Equal(t, `{"state":"", "time":"0s"}`, first.String())
+	_, ok = sw.LastLap()
+	assert.False(t, ok)
+	missing, _ := sw.LapByState("missing")
+	assert.Equal(t, `{"state":"", "time":"0s"}`, missing.String())
+
+	sw.Lap("db")
+	sw.LapWithData("cache", map[string]interface{}{"hit": true})
+	sw.LapWithData("db", map[string]interface{}{"rows": 2})
+	sw.Lap("render")
+
+	first, ok = sw.FirstLap()
+	assert.True(t, ok)
+	assert.Equal(t, "db", first.State())
+	last, ok := sw.LastLap()
+	assert.True(t, ok)
+	assert.Equal(t, "render", last.State())
+
+	db, ok := sw.LapByState("db")
+	assert.True(t, ok)
+	assert.Equal(t, 2, db.data["rows"])
+	_, ok = sw.LapByState("missing")
+	assert.False(t, ok)
+
+	sw.SetMaxLaps(1)
+	sw.Lap("parse")
+	_, ok = sw.LapByState("db")
+	assert.False(t, ok, "evicted")
+
+	sw.Reset(0, true)
+	_, ok = sw.LapByState("parse")
+	assert.False(t, ok)
+}
+
+func TestLapByStateWithoutIndex(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{{state: "db", duration: time.Millisecond}, {state: "db", duration: 2 * time.Millisecond}}
+
+	db, ok := sw.LapByState("db")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Millisecond, db.Duration())
+}
+
+func TestLapsBetween(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{
+		{state: "a", offset: 0, duration: time.Minute},
+		{state: "b", offset: time.Minute, duration: 90 * time.Second},
+		{state: "c", offset: 150 * time.Second, duration: time.Minute},
+		{state: "d", offset: 210 * time.Second, duration: time.Minute},
+	}
+
+	states := func(laps []Lap) []string {
+		var result []string
+		for _, lap := range laps {
+			result = append(result, lap.State())
+		}
+		return result
+	}
+	assert.Equal(t, []string{"c"}, states(sw.LapsBetween(2*time.Minute, 3*time.Minute)))
+	assert.Equal(t, []string{"b", "c"}, states(sw.LapsBetween(time.Minute, 210*time.Second)))
+	assert.Empty(t, sw.LapsBetween(5*time.Minute, 6*time.Minute))
+	assert.Empty(t, sw.LapsBetween(time.Minute, 0))
+
+	sw.laps = append(sw.laps, Lap{state: "task", offset: time.Second, duration: time.Second})
+	assert.Equal(t, []string{"c"}, states(sw.LapsBetween(2*time.Minute, 3*time.Minute)), "out of order")
+	assert.Equal(t, []string{"task"}, states(sw.LapsBetween(time.Second, 2*time.Second)))
+}
@@ -0,0 +1,82 @@
+package stopwatch
+
+import (
+	"context"
+	"time"
+)
+
+// TimedOutKey is the lap data key flagging laps of MeasureWithTimeout exceeding their timeout
+const TimedOutKey = "timed_out"
+
+// MeasureRetry calls fn up to 'attempts' times until it succeeds, recording
+// every attempt as a separate lap with the attempt number and the error (if any)
+// in the lap data. Between failed attempts it waits for 'backoff'. Laps start right
+// before fn is called, so neither the backoff nor the time before the call is counted.
+// It returns the error of the last attempt.
+func (s *Stopwatch) MeasureRetry(state string, attempts int, backoff time.Duration, fn func(attempt int) error) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		s.markLapStart()
+		err = fn(attempt)
+
+		data := map[string]interface{}{
+			"attempt": attempt,
+		}
+		if err != nil {
+			data["error"] = err.Error()
+		}
+		s.LapWithData(state, data)
+
+		if err == nil {
+			return nil
+		}
+		if attempt < attempts && backoff > 0 {
+			time.Sleep(backoff)
+		}
+	}
+	return err
+}
+
+// markLapStart makes the current lap start now, the time since the previous lap isn't recorded
+func (s *Stopwatch) markLapStart() {
+	s.markAt(s.now())
+}
+
+// markAt moves the start of the current lap to 'now', it's logged as EventMark
+func (s *Stopwatch) markAt(now time.Time) {
+	s.lock()
+	event := Event{Kind: EventMark, Time: now}
+	marked := s.walWrite(event)
+	if marked {
+		s.mark = s.ElapsedTimeFrom(now).Round(s.resolution)
+	}
+	log := s.eventLog
+	s.unlock()
+
+	if marked && log != nil {
+		log(event)
+	}
+}
+
+// MeasureWithTimeout calls fn with a context derived from ctx, canceled after the timeout d,
+// and records a single lap from the call to the return of fn, with the error (if any)
+// in the lap data. A lap exceeding the timeout gets TimedOutKey set to true,
+// even if fn ignored the context. It returns the error of fn.
+func (s *Stopwatch) MeasureWithTimeout(ctx context.Context, state string, d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	s.markLapStart()
+	err := fn(ctx)
+
+	var data map[string]interface{}
+	if err != nil {
+		data = map[string]interface{}{"error": err.Error()}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		data = copyData(data, 1)
+		data[TimedOutKey] = true
+	}
+	s.LapWithData(state, data)
+	return err
+}
@@ -0,0 +1,142 @@
+package stopwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureRetry(t *testing.T) {
+	sw := New(0, true)
+
+	err := sw.MeasureRetry("fetch", 5, 0, func(attempt int) error {
+		if attempt < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 3)
+	for i, lap := range laps {
+		assert.Equal(t, "fetch", lap.state)
+		assert.Equal(t, i+1, lap.data["attempt"])
+	}
+	assert.Equal(t, "unavailable", laps[0].data["error"])
+	assert.Equal(t, "unavailable", laps[1].data["error"])
+	assert.NotContains(t, laps[2].data, "error")
+}
+
+func TestMeasureRetryExhausted(t *testing.T) {
+	sw := New(0, true)
+
+	err := sw.MeasureRetry("fetch", 2, 0, func(attempt int) error {
+		return errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+	assert.Len(t, sw.Laps(), 2)
+}
+
+func TestMeasureWithTimeout(t *testing.T) {
+	sw := New(0, true)
+
+	err := sw.MeasureWithTimeout(context.Background(), "slow", time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	err = sw.MeasureWithTimeout(context.Background(), "fast", time.Minute, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	laps := sw.Laps()
+	if assert.Len(t, laps, 2) {
+		assert.Equal(t, map[string]interface{}{"error": context.DeadlineExceeded.Error(), TimedOutKey: true}, laps[0].data)
+		assert.True(t, laps[0].Duration() >= time.Millisecond)
+		assert.Nil(t, laps[1].data)
+	}
+}
+
+func TestMeasureRetryLapBoundaries(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := NewWithClock(0, true, clock)
+
+	sw.OnLap(func(Lap) { clock.now = clock.now.Add(200 * time.Millisecond) }) // like a backoff
+
+	clock.now = clock.now.Add(time.Second) // work before the call
+	err := sw.MeasureRetry("fetch", 2, time.Millisecond, func(attempt int) error {
+		clock.now = clock.now.Add(10 * time.Millisecond)
+		if attempt == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	laps := sw.Laps()
+	if assert.Len(t, laps, 2) {
+		assert.Equal(t, 10*time.Millisecond, laps[0].Duration())
+		assert.Equal(t, time.Second, laps[0].StartOffset())
+		assert.Equal(t, 10*time.Millisecond, laps[1].Duration())
+	}
+}
+
+func TestMeasureWithTimeoutParentContext(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := NewWithClock(0, true, clock)
+	clock.now = clock.now.Add(time.Second) // work before the call
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sw.MeasureWithTimeout(parent, "fetch", time.Minute, func(ctx context.Context) error {
+		clock.now = clock.now.Add(10 * time.Millisecond)
+		return ctx.Err()
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	laps := sw.Laps()
+	if assert.Len(t, laps, 1) {
+		assert.Equal(t, 10*time.Millisecond, laps[0].Duration())
+		assert.NotContains(t, laps[0].data, TimedOutKey)
+	}
+}
+
+func TestMeasureReplay(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := NewWithClock(0, true, clock)
+	var events []Event
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+
+	clock.now = clock.now.Add(30 * time.Millisecond) // work before the call
+	_ = sw.MeasureRetry("fetch", 2, 0, func(attempt int) error {
+		clock.now = clock.now.Add(5 * time.Millisecond)
+		if attempt == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	clock.now = clock.now.Add(20 * time.Millisecond)
+	_ = sw.MeasureWithTimeout(context.Background(), "save", time.Minute, func(ctx context.Context) error {
+		clock.now = clock.now.Add(5 * time.Millisecond)
+		return nil
+	})
+
+	replayed, err := Replay(events)
+	assert.NoError(t, err)
+	laps := replayed.Laps()
+	if assert.Len(t, laps, 3) {
+		for _, lap := range laps {
+			assert.Equal(t, 5*time.Millisecond, lap.Duration(), lap.State())
+		}
+		assert.Equal(t, 60*time.Millisecond, laps[2].StartOffset())
+	}
+	assert.True(t, Equal(sw, replayed, 0))
+}
@@ -0,0 +1,62 @@
+package stopwatch
+
+import (
+	"sync"
+	"time"
+)
+
+// MultiLap collects results of N goroutines fanned out from one place, see Stopwatch.MultiLap
+type MultiLap struct {
+	sw    *Stopwatch
+	state string
+	start time.Time
+
+	tasks int
+
+	mu      sync.Mutex
+	pending int
+	done    chan struct{}
+}
+
+// MultiLap starts a fan-out of n goroutines, each calls Done once. Every Done records
+// a lap from the fan-out start, Wait records the whole fan-out as a lap with the state.
+//
+//	ml := sw.MultiLap("fetch", len(shards))
+//	for _, shard := range shards {
+//		go func(shard string) {
+//			rows := fetch(shard)
+//			ml.Done("fetch."+shard, map[string]interface{}{"rows": rows})
+//		}(shard)
+//	}
+//	ml.Wait()
+func (s *Stopwatch) MultiLap(state string, n int) *MultiLap {
+	m := &MultiLap{sw: s, state: state, start: s.now(), tasks: n, pending: n, done: make(chan struct{})}
+	if n <= 0 {
+		close(m.done)
+	}
+	return m
+}
+
+// Done records the lap of a goroutine. Calls after all n goroutines are done are ignored.
+func (m *MultiLap) Done(state string, data map[string]interface{}) {
+	m.mu.Lock()
+	if m.pending <= 0 {
+		m.mu.Unlock()
+		return
+	}
+	m.pending--
+	last := m.pending == 0
+	m.mu.Unlock()
+
+	m.sw.recordTask(state, m.start, m.sw.now(), data)
+	if last {
+		close(m.done)
+	}
+}
+
+// Wait waits for all goroutines and records the fan-out wall time as a lap
+// with the number of goroutines in "tasks" lap data
+func (m *MultiLap) Wait() Lap {
+	<-m.done
+	return m.sw.LapWithData(m.state, map[string]interface{}{"tasks": m.tasks})
+}
@@ -0,0 +1,41 @@
+package stopwatch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiLap(t *testing.T) {
+	sw := New(0, true)
+	ml := sw.MultiLap("fetch", 3)
+	for i := 1; i <= 3; i++ {
+		go func(i int) {
+			time.Sleep(time.Duration(i) * time.Millisecond)
+			ml.Done(fmt.Sprintf("fetch.shard%d", i), map[string]interface{}{"rows": i})
+		}(i)
+	}
+	lap := ml.Wait()
+	ml.Done("extra", nil)
+
+	assert.Equal(t, "fetch", lap.State())
+	assert.Equal(t, 3, lap.data["tasks"])
+	assert.True(t, lap.Duration() >= 3*time.Millisecond)
+
+	laps := sw.Laps()
+	assert.Len(t, laps, 4)
+	var shards []string
+	for _, shard := range laps[:3] {
+		shards = append(shards, shard.State())
+		assert.True(t, shard.Duration() <= lap.Duration())
+	}
+	assert.ElementsMatch(t, []string{"fetch.shard1", "fetch.shard2", "fetch.shard3"}, shards)
+	assert.Equal(t, "fetch", laps[3].State())
+}
+
+func TestMultiLapNone(t *testing.T) {
+	lap := New(0, true).MultiLap("fetch", 0).Wait()
+	assert.Equal(t, 0, lap.data["tasks"])
+}
@@ -0,0 +1,43 @@
+package stopwatch
+
+import "fmt"
+
+// MustStart starts the stopwatch like Start, but panics if it is running already.
+// Must helpers are meant for tests and scripts, where a crash beats silently odd timings.
+func (s *Stopwatch) MustStart() {
+	if s.running() {
+		panic("stopwatch: start of a running stopwatch")
+	}
+	s.Start()
+}
+
+// MustStop stops the stopwatch like Stop, but panics if it is stopped already
+func (s *Stopwatch) MustStop() {
+	if !s.running() {
+		panic("stopwatch: stop of a stopped stopwatch")
+	}
+	s.Stop()
+}
+
+// MustLap records a lap like Lap, but panics if the stopwatch is stopped, the state is not
+// allowed by SetAllowedStates or the lap duration comes out negative, e.g. WithTime
+// is earlier than the previous lap
+func (s *Stopwatch) MustLap(state string, opts ...LapOption) Lap {
+	if !s.running() {
+		panic(fmt.Sprintf("stopwatch: lap %q of a stopped stopwatch", state))
+	}
+	if err := s.CheckState(state); err != nil {
+		panic("stopwatch: " + err.Error())
+	}
+	lap := s.Lap(state, opts...)
+	if lap.duration < 0 {
+		panic(fmt.Sprintf("stopwatch: negative duration %v of lap %q", lap.duration, state))
+	}
+	return lap
+}
+
+func (s *Stopwatch) running() bool {
+	s.rlock()
+	defer s.runlock()
+	return s.active()
+}
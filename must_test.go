@@ -0,0 +1,25 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMust(t *testing.T) {
+	sw := New(0, false)
+	assert.PanicsWithValue(t, "stopwatch: stop of a stopped stopwatch", sw.MustStop)
+	assert.PanicsWithValue(t, `stopwatch: lap "db" of a stopped stopwatch`, func() { sw.MustLap("db") })
+
+	assert.NotPanics(t, sw.MustStart)
+	assert.PanicsWithValue(t, "stopwatch: start of a running stopwatch", sw.MustStart)
+	assert.NotPanics(t, func() { sw.MustLap("db") })
+
+	sw.SetAllowedStates("db")
+	assert.PanicsWithValue(t, `stopwatch: unexpected lap state "cache"`, func() { sw.MustLap("cache") })
+
+	assert.Panics(t, func() { sw.MustLap("db", WithTime(sw.start.Add(-time.Second))) })
+
+	assert.NotPanics(t, sw.MustStop)
+}
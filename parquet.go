@@ -0,0 +1,284 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+)
+
+// Parquet physical and converted types, and the thrift compact protocol types used by the footer
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetNoConversion    = -1
+
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+var parquetMagic = []byte("PAR1")
+
+// WriteParquet writes laps to w as a Parquet file with a stable schema: state, started_at
+// (timestamp, µs), offset_ms, duration_ms, correlation_id, run_id and data as JSON. Optional values
+// are empty strings. The file is uncompressed, with a single row group, so it is meant for
+// runs of a reasonable size. It loads into DuckDB, Spark or Athena as is.
+func (s *Stopwatch) WriteParquet(w io.Writer) error {
+	laps := s.Laps()
+	state := newParquetColumn("state", parquetByteArray, parquetUTF8)
+	startedAt := newParquetColumn("started_at", parquetInt64, parquetTimestampMicros)
+	offset := newParquetColumn("offset_ms", parquetDouble, parquetNoConversion)
+	duration := newParquetColumn("duration_ms", parquetDouble, parquetNoConversion)
+	correlationID := newParquetColumn("correlation_id", parquetByteArray, parquetUTF8)
+	runID := newParquetColumn(RunIDKey, parquetByteArray, parquetUTF8)
+	data := newParquetColumn("data", parquetByteArray, parquetUTF8)
+
+	for _, lap := range laps {
+		state.string(lap.state)
+		startedAt.int64(lap.end.Add(-lap.duration).UnixNano() / 1000)
+		offset.double(milliseconds(lap.offset))
+		duration.double(milliseconds(lap.duration))
+		correlationID.string(lap.correlationID)
+		runID.string(lap.runID)
+		encoded := ""
+		if len(lap.data) > 0 {
+			b, err := json.Marshal(lap.data)
+			if err != nil {
+				return err
+			}
+			encoded = string(b)
+		}
+		data.string(encoded)
+	}
+	return writeParquet(w, len(laps), state, startedAt, offset, duration, correlationID, runID, data)
+}
+
+// WriteParquetSummary writes a row per lap state to w as a Parquet file: state, count,
+// total_ms and mean_ms, sorted by state. Laps not kept are counted too, see StateCounts.
+func (s *Stopwatch) WriteParquetSummary(w io.Writer) error {
+	counts := s.StateCounts()
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	state := newParquetColumn("state", parquetByteArray, parquetUTF8)
+	count := newParquetColumn("count", parquetInt64, parquetNoConversion)
+	total := newParquetColumn("total_ms", parquetDouble, parquetNoConversion)
+	mean := newParquetColumn("mean_ms", parquetDouble, parquetNoConversion)
+	for _, name := range states {
+		c := counts[name]
+		state.string(name)
+		count.int64(int64(c.Count))
+		total.double(milliseconds(c.Total))
+		mean.double(milliseconds(c.Total) / float64(c.Count))
+	}
+	return writeParquet(w, len(states), state, count, total, mean)
+}
+
+// parquetColumn is a required column with PLAIN encoded values
+type parquetColumn struct {
+	name      string
+	kind      int32
+	converted int32
+	values    bytes.Buffer
+}
+
+func newParquetColumn(name string, kind, converted int32) *parquetColumn {
+	return &parquetColumn{name: name, kind: kind, converted: converted}
+}
+
+func (c *parquetColumn) int64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	c.values.Write(b[:])
+}
+
+func (c *parquetColumn) double(v float64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	c.values.Write(b[:])
+}
+
+func (c *parquetColumn) string(v string) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(len(v)))
+	c.values.Write(b[:])
+	c.values.WriteString(v)
+}
+
+// writeParquet writes a file with a single row group, a single data page per column
+func writeParquet(w io.Writer, rows int, columns ...*parquetColumn) error {
+	var file bytes.Buffer
+	file.Write(parquetMagic)
+
+	type chunk struct {
+		offset, size int64
+	}
+	chunks := make([]chunk, len(columns))
+	if rows > 0 {
+		for i, c := range columns {
+			var header thriftWriter
+			header.i32(1, 0) // DATA_PAGE
+			header.i32(2, int32(c.values.Len()))
+			header.i32(3, int32(c.values.Len()))
+			header.beginStruct(5)
+			header.i32(1, int32(rows))
+			header.i32(2, 0) // PLAIN
+			header.i32(3, 3) // RLE, no levels are written for required columns
+			header.i32(4, 3)
+			header.endStruct()
+			header.stop()
+
+			chunks[i] = chunk{offset: int64(file.Len()), size: int64(len(header.buf) + c.values.Len())}
+			file.Write(header.buf)
+			file.Write(c.values.Bytes())
+		}
+	}
+
+	var meta thriftWriter
+	meta.i32(1, 1) // version
+	meta.listHeader(2, thriftStruct, len(columns)+1)
+	meta.beginElement()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.endStruct()
+	for _, c := range columns {
+		meta.beginElement()
+		meta.i32(1, c.kind)
+		meta.i32(3, 0) // REQUIRED
+		meta.binary(4, c.name)
+		if c.converted != parquetNoConversion {
+			meta.i32(6, c.converted)
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, int64(rows))
+
+	if rows == 0 {
+		meta.listHeader(4, thriftStruct, 0)
+	} else {
+		meta.listHeader(4, thriftStruct, 1)
+		meta.beginElement()
+		meta.listHeader(1, thriftStruct, len(columns))
+		var totalSize int64
+		for i, c := range columns {
+			totalSize += chunks[i].size
+			meta.beginElement()
+			meta.i64(2, chunks[i].offset)
+			meta.beginStruct(3)
+			meta.i32(1, c.kind)
+			meta.listHeader(2, thriftI32, 1)
+			meta.varint(0) // PLAIN
+			meta.listHeader(3, thriftBinary, 1)
+			meta.varint(uint64(len(c.name)))
+			meta.buf = append(meta.buf, c.name...)
+			meta.i32(4, 0) // UNCOMPRESSED
+			meta.i64(5, int64(rows))
+			meta.i64(6, chunks[i].size)
+			meta.i64(7, chunks[i].size)
+			meta.i64(9, chunks[i].offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, totalSize)
+		meta.i64(3, int64(rows))
+		meta.endStruct()
+	}
+	meta.binary(6, "stopwatch version "+VERSION)
+	meta.stop()
+
+	file.Write(meta.buf)
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(meta.buf)))
+	file.Write(length[:])
+	file.Write(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// thriftWriter encodes structs with the thrift compact protocol, as Parquet metadata requires
+type thriftWriter struct {
+	buf    []byte
+	lastID int16
+	stack  []int16 // field IDs of enclosing structs
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf = appendUvarint(t.buf, v)
+}
+
+func (t *thriftWriter) field(id int16, kind byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|kind)
+	} else {
+		t.buf = append(t.buf, kind)
+		t.varint(uint64(uint16((id << 1) ^ (id >> 15))))
+	}
+	t.lastID = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftWriter) binary(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.varint(uint64(len(v)))
+	t.buf = append(t.buf, v...)
+}
+
+func (t *thriftWriter) listHeader(id int16, kind byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|kind)
+		return
+	}
+	t.buf = append(t.buf, 0xf0|kind)
+	t.varint(uint64(size))
+}
+
+// beginStruct starts a struct field
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.beginElement()
+}
+
+// beginElement starts a struct in a list
+func (t *thriftWriter) beginElement() {
+	t.stack = append(t.stack, t.lastID)
+	t.lastID = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.stop()
+	t.lastID = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftWriter) stop() {
+	t.buf = append(t.buf, 0)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
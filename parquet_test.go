@@ -0,0 +1,209 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// thriftReader decodes thrift compact structs into maps by field ID, to check the footer
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(kind byte) interface{} {
+	switch kind {
+	case 1, 2:
+		return kind == 1
+	case thriftI32, thriftI64, 4:
+		return r.zigzag()
+	case thriftBinary:
+		n := int(r.uvarint())
+		r.pos += n
+		return string(r.buf[r.pos-n : r.pos])
+	case thriftList:
+		header := r.buf[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.structure()
+	}
+	panic("unexpected thrift type")
+}
+
+func (r *thriftReader) structure() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var id int16
+	for {
+		header := r.buf[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+	}
+}
+
+func readParquetFooter(t *testing.T, file []byte) map[int16]interface{} {
+	assert.Equal(t, "PAR1", string(file[:4]))
+	assert.Equal(t, "PAR1", string(file[len(file)-4:]))
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thriftReader{buf: file[len(file)-8-length : len(file)-8]}
+	meta := footer.structure()
+	assert.Equal(t, length, footer.pos, "the whole footer is read")
+	return meta
+}
+
+func TestWriteParquet(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, true)
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{
+		{state: "parse", end: start.Add(5 * time.Millisecond), duration: 5 * time.Millisecond, correlationID: "req-1"},
+		{state: "db", offset: 5 * time.Millisecond, end: start.Add(15 * time.Millisecond), duration: 10 * time.Millisecond,
+			data: map[string]interface{}{"rows": 2}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteParquet(&buf))
+	file := buf.Bytes()
+
+	meta := readParquetFooter(t, file)
+	assert.EqualValues(t, 2, meta[3], "num_rows")
+
+	schema := meta[2].([]interface{})
+	var names []string
+	for _, element := range schema[1:] {
+		names = append(names, element.(map[int16]interface{})[4].(string))
+	}
+	assert.Equal(t, []string{"state", "started_at", "offset_ms", "duration_ms", "correlation_id", "run_id", "data"}, names)
+
+	rowGroups := meta[4].([]interface{})
+	assert.Len(t, rowGroups, 1)
+	columns := rowGroups[0].(map[int16]interface{})[1].([]interface{})
+	assert.Len(t, columns, 7)
+
+	// every column chunk starts with a page header followed by PLAIN values
+	values := func(column int) []byte {
+		columnMeta := columns[column].(map[int16]interface{})[3].(map[int16]interface{})
+		offset := int(columnMeta[9].(int64))
+		page := &thriftReader{buf: file, pos: offset}
+		header := page.structure()
+		assert.EqualValues(t, 2, header[5].(map[int16]interface{})[1], "num_values")
+		size := int(header[3].(int64))
+		assert.Equal(t, int(columnMeta[7].(int64)), page.pos-offset+size)
+		return file[page.pos : page.pos+size]
+	}
+
+	state := values(0)
+	assert.Equal(t, "\x05\x00\x00\x00parse\x02\x00\x00\x00db", string(state))
+
+	startedAt := values(1)
+	assert.Equal(t, start.UnixNano()/1000, int64(binary.LittleEndian.Uint64(startedAt[:8])))
+
+	duration := values(3)
+	assert.Equal(t, 10.0, math.Float64frombits(binary.LittleEndian.Uint64(duration[8:])))
+
+	data := values(6)
+	assert.Equal(t, "\x00\x00\x00\x00\x0a\x00\x00\x00{\"rows\":2}", string(data))
+}
+
+// parquetSchema returns physical type, repetition, name and converted type of columns
+func parquetSchema(meta map[int16]interface{}) [][]interface{} {
+	var columns [][]interface{}
+	for _, element := range meta[2].([]interface{})[1:] {
+		fields := element.(map[int16]interface{})
+		columns = append(columns, []interface{}{fields[1], fields[3], fields[4], fields[6]})
+	}
+	return columns
+}
+
+// parquetValues returns PLAIN encoded values of every column of a single page uncompressed file
+func parquetValues(t *testing.T, file []byte, meta map[int16]interface{}) [][]byte {
+	var values [][]byte
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	for _, column := range rowGroup[1].([]interface{}) {
+		columnMeta := column.(map[int16]interface{})[3].(map[int16]interface{})
+		assert.EqualValues(t, 0, columnMeta[4], "uncompressed")
+		page := &thriftReader{buf: file, pos: int(columnMeta[9].(int64))}
+		header := page.structure()
+		assert.EqualValues(t, 0, header[1], "data page")
+		size := int(header[3].(int64))
+		values = append(values, file[page.pos:page.pos+size])
+	}
+	return values
+}
+
+// testdata/laps.parquet has the laps of the test written by pqarrow.WriteTable of the Arrow
+// Go module v18.8.0, with plain uncompressed v1 data pages, no dictionaries and no statistics,
+// so the schema and the values of both files are compared as they are
+func TestWriteParquetGolden(t *testing.T) {
+	golden, err := ioutil.ReadFile("testdata/laps.parquet")
+	assert.NoError(t, err)
+
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := New(0, true)
+	sw.laps = []Lap{
+		{state: "parse", end: start.Add(5 * time.Millisecond), duration: 5 * time.Millisecond, correlationID: "req-1", runID: "run-1"},
+		{state: "db", offset: 5 * time.Millisecond, end: start.Add(15 * time.Millisecond), duration: 10 * time.Millisecond,
+			data: map[string]interface{}{"rows": 2}, runID: "run-1"},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteParquet(&buf))
+
+	expected, actual := readParquetFooter(t, golden), readParquetFooter(t, buf.Bytes())
+	assert.Equal(t, expected[3], actual[3], "num_rows")
+	assert.Equal(t, parquetSchema(expected), parquetSchema(actual))
+	assert.Equal(t, parquetValues(t, golden, expected), parquetValues(t, buf.Bytes(), actual))
+}
+
+func TestWriteParquetSummary(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("b")
+	sw.Lap("a")
+	sw.Lap("b")
+
+	var buf bytes.Buffer
+	assert.NoError(t, sw.WriteParquetSummary(&buf))
+	meta := readParquetFooter(t, buf.Bytes())
+	assert.EqualValues(t, 2, meta[3])
+	assert.Len(t, meta[2], 5)
+}
+
+func TestWriteParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, New(0, true).WriteParquet(&buf))
+	meta := readParquetFooter(t, buf.Bytes())
+	assert.EqualValues(t, 0, meta[3])
+	assert.Empty(t, meta[4])
+}
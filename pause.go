@@ -0,0 +1,61 @@
+package stopwatch
+
+import "time"
+
+// Pause is a period the stopwatch was stopped by Pause or Stop
+type Pause struct {
+	Reason string    `json:"reason,omitempty"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"` // zero while the stopwatch is stopped
+}
+
+// Duration returns the length of the pause, zero while it lasts
+func (p Pause) Duration() time.Duration {
+	if p.End.IsZero() {
+		return 0
+	}
+	return p.End.Sub(p.Start)
+}
+
+// Pause stops the stopwatch like Stop, recording why, e.g. "waiting on customer".
+// Start resumes it. Pauses with their reasons are in Pauses and FormattingModeJsonFull.
+func (s *Stopwatch) Pause(reason string) {
+	s.stopAt(s.now(), reason)
+}
+
+// Pauses returns pauses since the last Reset, the last one may still last.
+// Stopwatches created stopped aren't paused until they are started and stopped again.
+func (s *Stopwatch) Pauses() []Pause {
+	s.rlock()
+	defer s.runlock()
+	pauses := make([]Pause, len(s.pauses))
+	copy(pauses, s.pauses)
+	return pauses
+}
+
+// DetailedPause is a pause in FormattingModeJsonFull
+type DetailedPause struct {
+	Reason    string     `json:"reason,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"` // nil while it lasts
+	Ms        float64    `json:"ms"`                 // so far while it lasts
+}
+
+// detailedPauses must be called under the read lock
+func (s *Stopwatch) detailedPauses() []DetailedPause {
+	if len(s.pauses) == 0 {
+		return nil
+	}
+	pauses := make([]DetailedPause, len(s.pauses))
+	for i, pause := range s.pauses {
+		pauses[i] = DetailedPause{Reason: pause.Reason, StartedAt: pause.Start}
+		if pause.End.IsZero() {
+			pauses[i].Ms = milliseconds(s.now().Sub(pause.Start))
+			continue
+		}
+		end := pause.End
+		pauses[i].EndedAt = &end
+		pauses[i].Ms = milliseconds(pause.Duration())
+	}
+	return pauses
+}
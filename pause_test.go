@@ -0,0 +1,50 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := &fixedClock{now: start}
+	sw := NewWithClock(0, true, clock)
+	var events []Event
+	sw.SetEventLog(func(event Event) { events = append(events, event) })
+
+	clock.now = start.Add(time.Minute)
+	sw.Pause("waiting on customer")
+	clock.now = start.Add(3 * time.Minute)
+	sw.Start()
+	clock.now = start.Add(4 * time.Minute)
+	sw.Stop()
+
+	assert.Equal(t, []Pause{
+		{Reason: "waiting on customer", Start: start.Add(time.Minute), End: start.Add(3 * time.Minute)},
+		{Start: start.Add(4 * time.Minute)},
+	}, sw.Pauses())
+	assert.Equal(t, 2*time.Minute, sw.Pauses()[0].Duration())
+	assert.Equal(t, 2*time.Minute, sw.ElapsedTime())
+	assert.Equal(t, "waiting on customer", events[len(events)-3].Reason)
+
+	clock.now = start.Add(5 * time.Minute)
+	var full FullStopwatch
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	if assert.Len(t, full.Pauses, 2) {
+		assert.Equal(t, "waiting on customer", full.Pauses[0].Reason)
+		assert.Equal(t, 120000.0, full.Pauses[0].Ms)
+		assert.Nil(t, full.Pauses[1].EndedAt)
+		assert.Equal(t, 60000.0, full.Pauses[1].Ms)
+	}
+
+	replayed, err := Replay(events)
+	assert.NoError(t, err)
+	assert.Equal(t, sw.Pauses(), replayed.Pauses())
+
+	sw.Reset(0, true)
+	assert.Empty(t, sw.Pauses())
+}
@@ -0,0 +1,214 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// savedStopwatch is the file format of SaveTo
+type savedStopwatch struct {
+	Start         time.Time     `json:"start"`
+	Stop          *time.Time    `json:"stop,omitempty"`         // nil while running
+	ActiveSince   *time.Time    `json:"active_since,omitempty"` // nil while stopped
+	Intervals     []Interval    `json:"intervals,omitempty"`    // closed ones
+	Mark          time.Duration `json:"mark"`
+	Paused        time.Duration `json:"paused"`
+	Adjustments   []Adjustment  `json:"adjustments,omitempty"`
+	Pauses        []Pause       `json:"pauses,omitempty"`
+	CorrelationID string        `json:"correlation_id,omitempty"`
+	RunID         string        `json:"run_id,omitempty"`
+	Seq           uint64        `json:"seq,omitempty"`
+	Laps          []savedLap    `json:"laps"`
+}
+
+type savedLap struct {
+	State         string                 `json:"state"`
+	Offset        time.Duration          `json:"offset"`
+	End           time.Time              `json:"end"`
+	Duration      time.Duration          `json:"duration"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	Seq           uint64                 `json:"seq,omitempty"`
+	RunID         string                 `json:"run_id,omitempty"`
+}
+
+// SaveTo writes the timing state and laps to the file, so a job restarted from a checkpoint
+// can resume its timing with LoadFrom. The file is replaced atomically.
+// Settings like the formatting mode are not saved.
+func (s *Stopwatch) SaveTo(path string) error {
+	s.rlock()
+	saved := savedStopwatch{
+		Start:         s.start,
+		Mark:          s.mark,
+		Paused:        s.paused,
+		Intervals:     s.intervals,
+		Adjustments:   s.adjustments,
+		Pauses:        s.pauses,
+		CorrelationID: s.correlationID,
+		RunID:         s.runID,
+		Seq:           s.seq,
+		Laps:          make([]savedLap, len(s.laps)),
+	}
+	if s.active() {
+		activeSince := s.activeSince
+		saved.ActiveSince = &activeSince
+	} else {
+		stop := s.stop
+		saved.Stop = &stop
+	}
+	for i, lap := range s.laps {
+		saved.Laps[i] = savedLap{
+			State:         lap.state,
+			Offset:        lap.offset,
+			End:           lap.end,
+			Duration:      lap.duration,
+			Data:          lap.data,
+			CorrelationID: lap.correlationID,
+			Seq:           lap.seq,
+			RunID:         lap.runID,
+		}
+	}
+	s.runlock()
+
+	content, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails after the rename
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadFrom replaces the timing state and laps with those saved by SaveTo, keeping settings.
+// The elapsed time, active intervals, pauses and adjustments are restored as they were saved.
+// A stopwatch saved running keeps running since its original start, so the time the job
+// was down is counted too. Stop it before saving to leave the downtime out.
+// Lap data go through JSON, so numbers come back as float64.
+// The write-ahead log and the event log get the loaded state as a reset followed by
+// the saved starts, stops, adjustments and laps, so replaying them recovers it.
+func (s *Stopwatch) LoadFrom(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var saved savedStopwatch
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return err
+	}
+	events := saved.events()
+
+	s.lock()
+	if !s.walWrite(events...) {
+		err := s.walErr
+		s.unlock()
+		return err
+	}
+	log := s.eventLog
+	s.load(saved)
+	s.unlock()
+
+	if log != nil {
+		for _, event := range events {
+			log(event)
+		}
+	}
+	return nil
+}
+
+// load must be called under the lock
+func (s *Stopwatch) load(saved savedStopwatch) {
+	var stop time.Time
+	if saved.Stop != nil {
+		stop = *saved.Stop
+	}
+	s.resetLocked(saved.Start, stop)
+	if saved.ActiveSince != nil {
+		s.activeSince = *saved.ActiveSince
+	}
+	s.intervals = saved.Intervals
+	s.mark = saved.Mark
+	s.paused = saved.Paused
+	s.adjustments = saved.Adjustments
+	s.pauses = saved.Pauses
+	for _, adjustment := range saved.Adjustments {
+		s.adjusted += adjustment.Delta
+	}
+	s.correlationID = saved.CorrelationID
+	s.runID = saved.RunID
+	s.seq = saved.Seq
+	for _, saved := range saved.Laps {
+		lap := Lap{
+			formatter:     s.formatter,
+			state:         saved.State,
+			offset:        saved.Offset,
+			end:           saved.End,
+			duration:      saved.Duration,
+			data:          saved.Data,
+			correlationID: saved.CorrelationID,
+			seq:           saved.Seq,
+			runID:         saved.RunID,
+		}
+		s.laps = append(s.laps, lap)
+		s.indexLap(lap)
+	}
+	s.recapLocked()
+}
+
+// events describe the saved stopwatch as it was recorded: a reset, a start and a stop
+// per active interval, laps and adjustments in order of time
+func (saved savedStopwatch) events() []Event {
+	intervals := saved.Intervals
+	switch {
+	case saved.ActiveSince != nil:
+		intervals = append(intervals[:len(intervals):len(intervals)], Interval{Start: *saved.ActiveSince})
+	case len(intervals) == 0 && saved.Stop != nil:
+		// stopped since New or Reset with an offset
+		intervals = []Interval{{Start: saved.Start, End: *saved.Stop}}
+	}
+
+	first := saved.Start
+	if len(intervals) > 0 {
+		first = intervals[0].Start
+	}
+	events := []Event{{Kind: EventReset, Time: first}}
+	for i, interval := range intervals {
+		events = append(events, Event{Kind: EventStart, Time: interval.Start})
+		if !interval.End.IsZero() {
+			stop := Event{Kind: EventStop, Time: interval.End}
+			if i < len(saved.Pauses) {
+				stop.Reason = saved.Pauses[i].Reason
+			}
+			events = append(events, stop)
+		}
+	}
+	for _, lap := range saved.Laps {
+		events = append(events, Event{Kind: EventLap, Time: lap.End, State: lap.State, Data: lap.Data})
+	}
+	for _, adjustment := range saved.Adjustments {
+		events = append(events, Event{Kind: EventAdjust, Time: adjustment.Time, Delta: adjustment.Delta})
+	}
+
+	// the reset stays first, at the same time a lap follows a stop and precedes an adjustment
+	recorded := events[1:]
+	sort.SliceStable(recorded, func(i, j int) bool { return recorded[i].Time.Before(recorded[j].Time) })
+	return events
+}
@@ -0,0 +1,116 @@
+package stopwatch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveToLoadFrom(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	sw := New(time.Second, true)
+	sw.SetCorrelationID("job-1")
+	sw.LapWithData("download", map[string]interface{}{"files": 2})
+	assert.NoError(t, sw.SaveTo(path))
+
+	restored := New(0, false)
+	restored.SetFormattingMode(FormattingModeJsonSimpleObject)
+	assert.NoError(t, restored.LoadFrom(path))
+
+	assert.True(t, restored.active(), "still running")
+	assert.True(t, restored.ElapsedTime() >= time.Second)
+	assert.True(t, Equal(sw, restored, 0))
+	assert.Equal(t, 2.0, restored.Laps()[0].data["files"])
+	assert.Equal(t, "job-1", restored.Laps()[0].CorrelationID())
+	assert.Contains(t, restored.String(), `"correlation_id":"job-1"`, "settings are kept")
+
+	restored.Lap("upload")
+	assert.Equal(t, []string{"download", "upload"}, []string{restored.Laps()[0].State(), restored.Laps()[1].State()})
+}
+
+func TestSaveToStopped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	sw := New(time.Second, false)
+	assert.NoError(t, sw.SaveTo(path))
+	assert.NoError(t, sw.SaveTo(path), "replaces the file")
+
+	restored := New(0, true)
+	assert.NoError(t, restored.LoadFrom(path))
+	assert.False(t, restored.active())
+	assert.Equal(t, sw.ElapsedTime(), restored.ElapsedTime())
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "no temporary files left")
+}
+
+func TestLoadFromMissingFile(t *testing.T) {
+	assert.Error(t, New(0, true).LoadFrom(filepath.Join("does", "not", "exist")))
+}
+
+func TestLoadFromPaused(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+
+	clock := &fixedClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := NewWithClock(0, true, clock)
+	clock.now = clock.now.Add(time.Second)
+	sw.Lap("download")
+	sw.Pause("rate limit")
+	clock.now = clock.now.Add(time.Minute)
+	sw.Start()
+	clock.now = clock.now.Add(2 * time.Second)
+	sw.Lap("upload")
+	sw.AddElapsed(time.Second)
+	sw.Stop()
+	assert.NoError(t, sw.SaveTo(path))
+
+	wal, err := OpenWAL(filepath.Join(dir, "wal.ndjson"), false)
+	assert.NoError(t, err)
+	var logged []Event
+	wal.SetEventLog(func(event Event) { logged = append(logged, event) })
+	assert.NoError(t, wal.LoadFrom(path))
+
+	assert.Equal(t, 4*time.Second, wal.ElapsedTime())
+	assert.Equal(t, sw.ActiveIntervals(), wal.ActiveIntervals())
+	assert.Equal(t, sw.Pauses(), wal.Pauses())
+	assert.True(t, Equal(sw, wal, 0))
+	assert.NoError(t, wal.Close(context.Background()))
+
+	// the log gets the loaded state, so the recovered stopwatch is the same
+	recovered, err := OpenWAL(filepath.Join(dir, "wal.ndjson"), false)
+	assert.NoError(t, err)
+	defer recovered.Close(context.Background())
+	assert.False(t, recovered.active())
+	assert.Equal(t, 4*time.Second, recovered.ElapsedTime())
+	assert.Equal(t, sw.ActiveIntervals(), recovered.ActiveIntervals())
+	assert.Equal(t, sw.Pauses(), recovered.Pauses())
+	assert.True(t, Equal(sw, recovered, 0))
+
+	replayed, err := Replay(logged[3:]) // after the current state logged by SetEventLog
+	assert.NoError(t, err)
+	assert.True(t, Equal(sw, replayed, 0))
+
+	// resumed, the running interval starts at the resume rather than at the start
+	clock.now = clock.now.Add(time.Minute)
+	sw.Start()
+	clock.now = clock.now.Add(time.Second)
+	assert.NoError(t, sw.SaveTo(path))
+	resumed := NewWithClock(0, false, clock)
+	assert.NoError(t, resumed.LoadFrom(path))
+	assert.Equal(t, 5*time.Second, resumed.ElapsedTime())
+	assert.Equal(t, sw.ActiveIntervals(), resumed.ActiveIntervals())
+}
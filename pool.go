@@ -0,0 +1,127 @@
+package stopwatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pool keeps stopwatches by ID, e.g. of a request or a job, for operations spanning several
+// callbacks where no single scope owns the stopwatch. Stopwatches not used for longer than
+// the TTL are evicted.
+type Pool struct {
+	ttl     time.Duration
+	evicted func(id string, sw *Stopwatch)
+
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+	stop    chan struct{}
+	closed  bool
+}
+
+type poolEntry struct {
+	sw   *Stopwatch
+	used time.Time
+}
+
+// NewPool creates a pool evicting stopwatches unused for the TTL. Evicted is called with every
+// evicted or removed stopwatch, outside of the pool lock, e.g. to log or export it. It may be nil.
+// Close the pool to stop expiry and evict the remaining stopwatches.
+func NewPool(ttl time.Duration, evicted func(id string, sw *Stopwatch)) *Pool {
+	p := &Pool{
+		ttl:     ttl,
+		evicted: evicted,
+		entries: make(map[string]*poolEntry),
+		stop:    make(chan struct{}),
+	}
+	interval := ttl / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	go flushEvery(interval, p.stop, p.expire)
+	return p
+}
+
+// Get returns the stopwatch of the ID, starting a new one if there is none
+func (p *Pool) Get(id string) *Stopwatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, found := p.entries[id]
+	if !found {
+		entry = &poolEntry{sw: New(0, true)}
+		p.entries[id] = entry
+	}
+	entry.used = time.Now()
+	return entry.sw
+}
+
+// Put adds a stopwatch set up by the caller under the ID, replacing one without eviction
+func (p *Pool) Put(id string, sw *Stopwatch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries[id] = &poolEntry{sw: sw, used: time.Now()}
+}
+
+// Remove takes the stopwatch of the ID out of the pool, when the operation is complete,
+// and passes it to the evicted callback. It returns nil if there is no such stopwatch.
+func (p *Pool) Remove(id string) *Stopwatch {
+	p.mu.Lock()
+	entry, found := p.entries[id]
+	delete(p.entries, id)
+	p.mu.Unlock()
+
+	if !found {
+		return nil
+	}
+	if p.evicted != nil {
+		p.evicted(id, entry.sw)
+	}
+	return entry.sw
+}
+
+// Len returns the number of stopwatches in the pool
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+func (p *Pool) expire(ctx context.Context) error {
+	p.evict(time.Now().Add(-p.ttl))
+	return nil
+}
+
+// evict removes stopwatches last used before the deadline, all with zero deadline
+func (p *Pool) evict(deadline time.Time) {
+	p.mu.Lock()
+	expired := make(map[string]*Stopwatch)
+	for id, entry := range p.entries {
+		if deadline.IsZero() || entry.used.Before(deadline) {
+			expired[id] = entry.sw
+			delete(p.entries, id)
+		}
+	}
+	p.mu.Unlock()
+
+	if p.evicted == nil {
+		return
+	}
+	for id, sw := range expired {
+		p.evicted(id, sw)
+	}
+}
+
+// Close stops expiry and evicts all stopwatches, so their laps are flushed on shutdown
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stop)
+	p.mu.Unlock()
+
+	p.evict(time.Time{})
+	return nil
+}
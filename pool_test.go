@@ -0,0 +1,52 @@
+package stopwatch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	var mu sync.Mutex
+	evicted := map[string]*Stopwatch{}
+	pool := NewPool(time.Hour, func(id string, sw *Stopwatch) {
+		mu.Lock()
+		defer mu.Unlock()
+		evicted[id] = sw
+	})
+
+	sw := pool.Get("req-1")
+	assert.Same(t, sw, pool.Get("req-1"))
+	custom := New(0, false)
+	pool.Put("req-2", custom)
+	pool.Get("req-3")
+	assert.Equal(t, 3, pool.Len())
+
+	assert.Same(t, sw, pool.Remove("req-1"))
+	assert.Nil(t, pool.Remove("req-1"))
+	assert.Same(t, sw, evicted["req-1"])
+
+	assert.NoError(t, pool.Close(context.Background()))
+	assert.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, 0, pool.Len())
+	assert.Same(t, custom, evicted["req-2"])
+	assert.Len(t, evicted, 3)
+}
+
+func TestPoolExpiry(t *testing.T) {
+	evicted := make(chan string, 10)
+	pool := NewPool(20*time.Millisecond, func(id string, sw *Stopwatch) { evicted <- id })
+	defer pool.Close(context.Background())
+
+	pool.Get("req-1")
+	select {
+	case id := <-evicted:
+		assert.Equal(t, "req-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("stopwatch not evicted")
+	}
+	assert.Equal(t, 0, pool.Len())
+}
@@ -0,0 +1,30 @@
+package stopwatch
+
+// ProgressReporter is implemented by progress bars, so the stopwatch can drive them
+type ProgressReporter interface {
+	// SetTotal sets the expected number of laps
+	SetTotal(total int)
+	// Increment advances the progress by one lap
+	Increment()
+	// Describe shows the state of the last recorded lap
+	Describe(state string)
+}
+
+type progressSink struct {
+	reporter ProgressReporter
+}
+
+// NewProgressSink creates a sink updating the reporter on every lap.
+// The reporter gets total right away, pass it to AddSink:
+//
+//	sw.AddSink(stopwatch.NewProgressSink(bar, len(files)))
+func NewProgressSink(reporter ProgressReporter, total int) Sink {
+	reporter.SetTotal(total)
+	return &progressSink{reporter: reporter}
+}
+
+func (p *progressSink) WriteLap(lap Lap) error {
+	p.reporter.Describe(lap.state)
+	p.reporter.Increment()
+	return nil
+}
@@ -0,0 +1,29 @@
+package stopwatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProgress struct {
+	total, current int
+	description    string
+}
+
+func (f *fakeProgress) SetTotal(total int)    { f.total = total }
+func (f *fakeProgress) Increment()            { f.current++ }
+func (f *fakeProgress) Describe(state string) { f.description = state }
+
+func TestProgressSink(t *testing.T) {
+	progress := &fakeProgress{}
+	sw := New(0, true)
+	sw.AddSink(NewProgressSink(progress, 3))
+	assert.Equal(t, 3, progress.total)
+
+	sw.Lap("file1")
+	sw.Lap("file2")
+
+	assert.Equal(t, 2, progress.current)
+	assert.Equal(t, "file2", progress.description)
+}
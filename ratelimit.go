@@ -0,0 +1,53 @@
+package stopwatch
+
+import (
+	"time"
+)
+
+// rateWindow counts laps of a state within a second
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// SetRateLimit keeps at most perSecond laps of every state within a second, protecting memory
+// and sinks when a lap is called inside a hot loop by mistake. Dropped laps are counted,
+// see Dropped and StateCounts. Zero turns the limit off.
+func (s *Stopwatch) SetRateLimit(perSecond int) {
+	s.lock()
+	defer s.unlock()
+	s.rateLimit = perSecond
+	s.rateWindows = nil
+}
+
+// Dropped returns the number of laps dropped by the rate limit since the last Reset
+func (s *Stopwatch) Dropped() int {
+	s.rlock()
+	defer s.runlock()
+	return s.dropped
+}
+
+// rateAllowed must be called under the lock
+func (s *Stopwatch) rateAllowed(state string, now time.Time) bool {
+	if s.rateLimit <= 0 {
+		return true
+	}
+	if s.rateWindows == nil {
+		s.rateWindows = make(map[string]*rateWindow)
+	}
+
+	window, found := s.rateWindows[state]
+	if !found {
+		window = &rateWindow{}
+		s.rateWindows[state] = window
+	}
+	if now.Sub(window.start) >= time.Second {
+		window.start = now
+		window.count = 0
+	}
+	if window.count >= s.rateLimit {
+		return false
+	}
+	window.count++
+	return true
+}
@@ -0,0 +1,30 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	sw := New(0, true)
+	sw.SetRateLimit(2)
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		sw.LapWithDataAndTime(now, "hot", nil)
+	}
+	sw.LapWithDataAndTime(now, "cold", nil)
+
+	assert.Len(t, sw.Laps(), 3)
+	assert.Equal(t, 3, sw.Dropped())
+	assert.Equal(t, 5, sw.StateCounts()["hot"].Count)
+
+	// the next second opens a new window
+	sw.LapWithDataAndTime(now.Add(time.Second), "hot", nil)
+	assert.Len(t, sw.Laps(), 4)
+
+	sw.Reset(0, true)
+	assert.Zero(t, sw.Dropped())
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+)
+
+// Snapshot is a single run of a stopwatch to be included into a report
+type Snapshot struct {
+	Name string
+	Time time.Time
+	Laps []Lap
+}
+
+// SnapshotOf takes a snapshot of the stopwatch laps
+func SnapshotOf(name string, sw *stopwatch.Stopwatch) Snapshot {
+	swLaps := sw.Laps()
+	laps := make([]Lap, len(swLaps))
+	for i, lap := range swLaps {
+		laps[i] = Lap{State: lap.State(), Duration: lap.Duration()}
+	}
+	return Snapshot{Name: name, Time: time.Now(), Laps: laps}
+}
+
+const (
+	chartWidth     = 240
+	chartHeight    = 40
+	histogramBins  = 10
+	trendPointSize = 2
+)
+
+// stateReport is what the template shows per state
+type stateReport struct {
+	State                 string
+	Runs                  int
+	Min, Median, P90, Max time.Duration
+	Trend                 template.HTML
+	Distribution          template.HTML
+	totals                []time.Duration // per snapshot, zero if the state is missing
+	present               []bool
+}
+
+// Generate writes a standalone HTML report comparing snapshots: per-state statistics,
+// a trend of the state total across snapshots, and its distribution.
+func Generate(snapshots []Snapshot, w io.Writer) error {
+	states := collectStates(snapshots)
+	for _, state := range states {
+		state.calculate()
+	}
+
+	data := struct {
+		Runs        int
+		First, Last string
+		States      []*stateReport
+	}{Runs: len(snapshots), States: states}
+	if len(snapshots) > 0 {
+		data.First = snapshots[0].Name
+		data.Last = snapshots[len(snapshots)-1].Name
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+// collectStates sums up every state per snapshot, states keep the order of first appearance
+func collectStates(snapshots []Snapshot) []*stateReport {
+	var states []*stateReport
+	byName := map[string]*stateReport{}
+
+	for i, snapshot := range snapshots {
+		for _, lap := range snapshot.Laps {
+			state, found := byName[lap.State]
+			if !found {
+				state = &stateReport{
+					State:   lap.State,
+					totals:  make([]time.Duration, len(snapshots)),
+					present: make([]bool, len(snapshots)),
+				}
+				byName[lap.State] = state
+				states = append(states, state)
+			}
+			state.totals[i] += lap.Duration
+			state.present[i] = true
+		}
+	}
+	return states
+}
+
+func (s *stateReport) calculate() {
+	var values []time.Duration
+	for i, total := range s.totals {
+		if s.present[i] {
+			values = append(values, total)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	s.Runs = len(values)
+	s.Min = values[0]
+	s.Max = values[len(values)-1]
+	s.Median = percentile(values, 50)
+	s.P90 = percentile(values, 90)
+	s.Trend = trendChart(s.totals, s.present, s.Max)
+	s.Distribution = histogram(values)
+}
+
+// percentile uses the nearest-rank method on sorted values
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func trendChart(totals []time.Duration, present []bool, max time.Duration) template.HTML {
+	var points []string
+	step := float64(chartWidth)
+	if len(totals) > 1 {
+		step = float64(chartWidth) / float64(len(totals)-1)
+	}
+	for i, total := range totals {
+		if !present[i] {
+			continue
+		}
+		x := float64(i) * step
+		y := float64(chartHeight)
+		if max > 0 {
+			y -= float64(total) / float64(max) * (chartHeight - trendPointSize)
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	return template.HTML(fmt.Sprintf(
+		`<svg width="%d" height="%d"><polyline fill="none" stroke="#1f77b4" stroke-width="%d" points="%s"/></svg>`,
+		chartWidth, chartHeight, trendPointSize, strings.Join(points, " ")))
+}
+
+func histogram(sorted []time.Duration) template.HTML {
+	min, max := sorted[0], sorted[len(sorted)-1]
+	bins := make([]int, histogramBins)
+	for _, value := range sorted {
+		bin := 0
+		if max > min {
+			bin = int(float64(value-min) / float64(max-min) * histogramBins)
+		}
+		if bin == histogramBins {
+			bin--
+		}
+		bins[bin]++
+	}
+
+	highest := 0
+	for _, count := range bins {
+		if count > highest {
+			highest = count
+		}
+	}
+
+	var bars strings.Builder
+	barWidth := chartWidth / histogramBins
+	for i, count := range bins {
+		height := float64(count) / float64(highest) * chartHeight
+		fmt.Fprintf(&bars, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="#ff7f0e"/>`,
+			i*barWidth, chartHeight-height, barWidth-1, height)
+	}
+
+	return template.HTML(fmt.Sprintf(`<svg width="%d" height="%d">%s</svg>`, chartWidth, chartHeight, bars.String()))
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Stopwatch report</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Stopwatch report</h1>
+<p>{{.Runs}} runs{{if .Runs}}, from {{.First}} to {{.Last}}{{end}}</p>
+<table>
+<tr><th>State</th><th>Runs</th><th>Min</th><th>Median</th><th>P90</th><th>Max</th><th>Trend</th><th>Distribution</th></tr>
+{{range .States}}<tr><td>{{.State}}</td><td>{{.Runs}}</td><td>{{.Min}}</td><td>{{.Median}}</td><td>{{.P90}}</td><td>{{.Max}}</td><td>{{.Trend}}</td><td>{{.Distribution}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	snapshots := []Snapshot{
+		{Name: "run1", Laps: []Lap{{State: "db", Duration: 10 * time.Millisecond}, {State: "<render>", Duration: time.Millisecond}}},
+		{Name: "run2", Laps: []Lap{{State: "db", Duration: 30 * time.Millisecond}}},
+		{Name: "run3", Laps: []Lap{{State: "db", Duration: 20 * time.Millisecond}, {State: "db", Duration: 5 * time.Millisecond}}},
+	}
+
+	var buf bytes.Buffer
+	err := Generate(snapshots, &buf)
+	assert.NoError(t, err)
+
+	html := buf.String()
+	assert.Contains(t, html, "3 runs, from run1 to run3")
+	assert.Contains(t, html, "<td>db</td><td>3</td><td>10ms</td><td>25ms</td><td>30ms</td><td>30ms</td>")
+	assert.Contains(t, html, "<td>&lt;render&gt;</td><td>1</td>")
+	assert.Contains(t, html, `points="0.0,27.3 120.0,2.0 240.0,8.3"`)
+}
+
+func TestGenerateEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	err := Generate(nil, &buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "0 runs")
+}
+
+func TestSnapshotOf(t *testing.T) {
+	sw := stopwatch.New(0, true)
+	sw.Lap("db")
+
+	snapshot := SnapshotOf("run1", sw)
+	assert.Equal(t, "run1", snapshot.Name)
+	assert.Len(t, snapshot.Laps, 1)
+	assert.Equal(t, "db", snapshot.Laps[0].State)
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// slowestInstances is the number of slowest laps kept per state by MergeReports
+const slowestInstances = 3
+
+// Merged is an aggregated report of stopwatch dumps, e.g. of shards of a batch job
+type Merged struct {
+	Dumps  int
+	States []MergedState
+}
+
+// MergedState aggregates laps of a state across dumps
+type MergedState struct {
+	State string
+	Laps  int
+	// Dumps is the number of dumps having laps of the state
+	Dumps                        int
+	Total, Min, Median, P90, Max time.Duration
+	// Slowest are the slowest laps of the state, the slowest first
+	Slowest []Instance
+}
+
+// Instance is a lap in one of merged dumps
+type Instance struct {
+	// Dump is the index of the dump in the arguments of MergeReports
+	Dump     int
+	Duration time.Duration
+	Data     map[string]interface{}
+}
+
+// MergeReports parses dumps of stopwatches, in any format supported by Parse,
+// and aggregates them into per-state totals, distributions and slowest laps.
+// States keep the order of their first appearance.
+func MergeReports(readers ...io.Reader) (*Merged, error) {
+	dumps := make([][]Lap, len(readers))
+	for i, r := range readers {
+		laps, err := Parse(r)
+		if err != nil {
+			return nil, fmt.Errorf("dump %d: %w", i, err)
+		}
+		dumps[i] = laps
+	}
+	return MergeLaps(dumps...), nil
+}
+
+// MergeLaps aggregates laps of dumps like MergeReports, e.g. of dumps read by ParseUnit
+func MergeLaps(dumps ...[]Lap) *Merged {
+	merged := &Merged{Dumps: len(dumps)}
+	byName := map[string]int{}
+	var durations [][]time.Duration
+	var lastDump []int
+
+	for i, laps := range dumps {
+		for _, lap := range laps {
+			index, found := byName[lap.State]
+			if !found {
+				index = len(merged.States)
+				byName[lap.State] = index
+				merged.States = append(merged.States, MergedState{State: lap.State})
+				durations = append(durations, nil)
+				lastDump = append(lastDump, -1)
+			}
+			state := &merged.States[index]
+			state.Laps++
+			state.Total += lap.Duration
+			if lastDump[index] != i {
+				state.Dumps++
+				lastDump[index] = i
+			}
+			durations[index] = append(durations[index], lap.Duration)
+			state.addInstance(Instance{Dump: i, Duration: lap.Duration, Data: lap.Data})
+		}
+	}
+
+	for i := range merged.States {
+		values := durations[i]
+		sort.Slice(values, func(a, b int) bool { return values[a] < values[b] })
+		state := &merged.States[i]
+		state.Min = values[0]
+		state.Median = percentile(values, 50)
+		state.P90 = percentile(values, 90)
+		state.Max = values[len(values)-1]
+	}
+	return merged
+}
+
+// addInstance keeps the slowest laps, sorted from the slowest
+func (s *MergedState) addInstance(instance Instance) {
+	i := sort.Search(len(s.Slowest), func(i int) bool { return s.Slowest[i].Duration < instance.Duration })
+	if i >= slowestInstances {
+		return
+	}
+	s.Slowest = append(s.Slowest, Instance{})
+	copy(s.Slowest[i+1:], s.Slowest[i:])
+	s.Slowest[i] = instance
+	if len(s.Slowest) > slowestInstances {
+		s.Slowest = s.Slowest[:slowestInstances]
+	}
+}
+
+// RenderMerged writes the merged report as a table, naming dumps of the slowest laps by names,
+// their indexes are used if names are missing
+func RenderMerged(merged *Merged, w io.Writer, names ...string) error {
+	rows := [][]string{{"STATE", "LAPS", "DUMPS", "TOTAL", "MIN", "MEDIAN", "P90", "MAX", "SLOWEST IN"}}
+	for _, state := range merged.States {
+		slowest := state.Slowest[0].Dump
+		dump := strconv.Itoa(slowest)
+		if slowest < len(names) {
+			dump = names[slowest]
+		}
+		rows = append(rows, []string{
+			state.State,
+			strconv.Itoa(state.Laps),
+			strconv.Itoa(state.Dumps),
+			state.Total.String(),
+			state.Min.String(),
+			state.Median.String(),
+			state.P90.String(),
+			state.Max.String(),
+			dump,
+		})
+	}
+	return writeRows(w, rows, []bool{true, false, false, false, false, false, false, false, true})
+}
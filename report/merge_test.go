@@ -0,0 +1,84 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeReports(t *testing.T) {
+	merged, err := MergeReports(
+		strings.NewReader(`[{"state":"db", "time":"10ms", "shard":"1"}, {"state":"render", "time":"1ms"}, {"state":"db", "time":"30ms"}]`),
+		strings.NewReader(`{"db":20, "render":2}`),
+		strings.NewReader(`[{"state":"db","ms":40,"offset_ms":0,"data":{"shard":"3"}}]`),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, merged.Dumps)
+
+	if assert.Len(t, merged.States, 2) {
+		db := merged.States[0]
+		assert.Equal(t, "db", db.State)
+		assert.Equal(t, 4, db.Laps)
+		assert.Equal(t, 3, db.Dumps)
+		assert.Equal(t, 100*time.Millisecond, db.Total)
+		assert.Equal(t, 10*time.Millisecond, db.Min)
+		assert.Equal(t, 20*time.Millisecond, db.Median)
+		assert.Equal(t, 40*time.Millisecond, db.P90)
+		assert.Equal(t, 40*time.Millisecond, db.Max)
+		assert.Equal(t, []Instance{
+			{Dump: 2, Duration: 40 * time.Millisecond, Data: map[string]interface{}{"shard": "3"}},
+			{Dump: 0, Duration: 30 * time.Millisecond},
+			{Dump: 1, Duration: 20 * time.Millisecond},
+		}, db.Slowest)
+
+		render := merged.States[1]
+		assert.Equal(t, 2, render.Dumps)
+		assert.Equal(t, 3*time.Millisecond, render.Total)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, RenderMerged(merged, &buf, "pod-a", "pod-b"))
+	assert.Equal(t, strings.Join([]string{
+		"STATE   LAPS  DUMPS  TOTAL   MIN  MEDIAN   P90   MAX  SLOWEST IN",
+		"db         4      3  100ms  10ms    20ms  40ms  40ms  2",
+		"render     2      2    3ms   1ms     1ms   2ms   2ms  pod-b",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestMergeReportsFull(t *testing.T) {
+	sw := stopwatch.New(0, true)
+	sw.SetCorrelationID("req-1")
+	sw.LapWithData("db", map[string]interface{}{"rows": 2})
+	sw.SetFormattingMode(stopwatch.FormattingModeJsonFull)
+	full := sw.String()
+	sw.SetFormattingMode(stopwatch.FormattingModeJsonSimpleObject)
+
+	merged, err := MergeReports(strings.NewReader(full), strings.NewReader(sw.String()))
+	assert.NoError(t, err)
+	if assert.Len(t, merged.States, 1) {
+		assert.Equal(t, 2, merged.States[0].Dumps)
+	}
+}
+
+func TestMergeReportsInvalid(t *testing.T) {
+	_, err := MergeReports(strings.NewReader(`[]`), strings.NewReader(`"lap"`))
+	assert.EqualError(t, err, "dump 1: unexpected lap, expected array or object of laps")
+}
+
+func TestMergeLaps(t *testing.T) {
+	a, err := ParseUnit(strings.NewReader(`{"db":2000}`), time.Microsecond)
+	assert.NoError(t, err)
+	b, err := Parse(strings.NewReader(`{"db":3}`))
+	assert.NoError(t, err)
+
+	merged := MergeLaps(a, b)
+	assert.Equal(t, 2, merged.Dumps)
+	if assert.Len(t, merged.States, 1) {
+		assert.Equal(t, 5*time.Millisecond, merged.States[0].Total)
+	}
+}
@@ -0,0 +1,234 @@
+// Package report reads serialized stopwatches back and renders them for humans
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+)
+
+// Lap is a lap read back from a serialized stopwatch
+type Lap struct {
+	State    string
+	Duration time.Duration
+	Data     map[string]interface{}
+}
+
+// ErrUnsupportedMode is returned by Parse for output of a formatting mode it doesn't read
+var ErrUnsupportedMode = errors.New("unsupported formatting mode")
+
+// Parse reads a stopwatch serialized in FormattingModeJsonArray, FormattingModeJsonSimpleObject,
+// FormattingModeJsonMsObject, FormattingModeJsonDetailed, FormattingModeNDJSON (also written
+// by NDJSONSink and FileSink), FormattingModeJsonFull or FormattingModeGoogleCloud.
+// Lap times must be either Go durations ("1.5ms") or milliseconds as numbers.
+// Output of other modes, like FormattingModeECS or FormattingModeJsonSummary,
+// gets an error wrapping ErrUnsupportedMode.
+func Parse(r io.Reader) ([]Lap, error) {
+	return ParseUnit(r, time.Millisecond)
+}
+
+// ParseUnit reads a stopwatch like Parse, lap times written as numbers in object modes
+// are in the unit, e.g. time.Microsecond for FormattingModeJsonIntObject by default,
+// see Stopwatch.SetIntegerUnit. Milliseconds of FormattingModeJsonDetailed laps are
+// always milliseconds.
+func ParseUnit(r io.Reader, unit time.Duration) ([]Lap, error) {
+	if unit <= 0 {
+		return nil, fmt.Errorf("invalid unit %v", unit)
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('['):
+		return parseArray(dec)
+	case json.Delim('{'):
+		return parseObject(dec, unit)
+	default:
+		return nil, fmt.Errorf("unexpected %v, expected array or object of laps", token)
+	}
+}
+
+// parseArray reads [{"state":"db", "time":"10ms", "extra":"data"}, ...]
+// or laps of FormattingModeJsonDetailed [{"state":"db", "ms":10, "data":{...}}, ...]
+func parseArray(dec *json.Decoder) ([]Lap, error) {
+	var laps []Lap
+	for dec.More() {
+		var item map[string]interface{}
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+		lap, err := parseLap(item)
+		if err != nil {
+			return nil, err
+		}
+		laps = append(laps, lap)
+	}
+	return laps, nil
+}
+
+func parseLap(item map[string]interface{}) (Lap, error) {
+	if mode := unsupportedMode(item); mode != "" {
+		return Lap{}, fmt.Errorf("%w %s", ErrUnsupportedMode, mode)
+	}
+	state, _ := item["state"].(string)
+	if _, detailed := item["ms"]; detailed {
+		duration, err := parseDuration(item["ms"], time.Millisecond)
+		if err != nil {
+			return Lap{}, fmt.Errorf("lap %q: %w", state, err)
+		}
+		data, _ := item["data"].(map[string]interface{})
+		return Lap{State: state, Duration: duration, Data: data}, nil
+	}
+
+	duration, err := parseDuration(item["time"], time.Millisecond)
+	if err != nil {
+		return Lap{}, fmt.Errorf("lap %q: %w", state, err)
+	}
+	delete(item, "state")
+	delete(item, "time")
+	delete(item, stopwatch.CorrelationIDKey)
+
+	lap := Lap{State: state, Duration: duration}
+	if len(item) > 0 {
+		lap.Data = item
+	}
+	return lap, nil
+}
+
+// parseObject reads {"db":"10ms", ...} or {"db":10.0, ...} keeping the order of keys,
+// a stopwatch in FormattingModeJsonFull with laps in "laps", or the first lap of FormattingModeNDJSON
+func parseObject(dec *json.Decoder, unit time.Duration) ([]Lap, error) {
+	type entry struct {
+		state string
+		value interface{}
+	}
+	var entries []entry
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		state, _ := token.(string)
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if items, full := value.([]interface{}); full && state == "laps" {
+			return parseFullLaps(items)
+		}
+		entries = append(entries, entry{state, value})
+	}
+
+	fields := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		fields[entry.state] = entry.value
+	}
+	if mode := unsupportedMode(fields); mode != "" {
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedMode, mode)
+	}
+	if _, line := fields["state"].(string); line && fields["ms"] != nil {
+		if _, err := dec.Token(); err != nil { // the end of the first line
+			return nil, err
+		}
+		return parseLines(dec, fields)
+	}
+
+	var laps []Lap
+	for _, entry := range entries {
+		if entry.state == stopwatch.CorrelationIDKey {
+			continue
+		}
+		duration, err := parseDuration(entry.value, unit)
+		if err != nil {
+			return nil, fmt.Errorf("lap %q: %w", entry.state, err)
+		}
+		laps = append(laps, Lap{State: entry.state, Duration: duration})
+	}
+	return laps, nil
+}
+
+// parseLines reads laps of FormattingModeNDJSON, one JSON value per line, after the first one
+func parseLines(dec *json.Decoder, first map[string]interface{}) ([]Lap, error) {
+	lap, err := parseLap(first)
+	if err != nil {
+		return nil, err
+	}
+	laps := []Lap{lap}
+	for dec.More() {
+		var item map[string]interface{}
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+		lap, err := parseLap(item)
+		if err != nil {
+			return nil, err
+		}
+		laps = append(laps, lap)
+	}
+	return laps, nil
+}
+
+// unsupportedMode recognizes output of formatting modes Parse doesn't read by their fields,
+// it returns an empty mode for anything else
+func unsupportedMode(fields map[string]interface{}) stopwatch.FormattingMode {
+	has := func(key string) bool {
+		_, found := fields[key]
+		return found
+	}
+	switch {
+	case has("@timestamp") && has("event"):
+		return stopwatch.FormattingModeECS
+	case has("_aws"):
+		return stopwatch.FormattingModeCloudWatchEMF
+	case has("count") && has("total_ms"):
+		return stopwatch.FormattingModeJsonSummary
+	case has("run_id") && has("total_ms"):
+		return stopwatch.FormattingModeFlat
+	}
+	return ""
+}
+
+func parseFullLaps(items []interface{}) ([]Lap, error) {
+	laps := make([]Lap, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected lap %v", item)
+		}
+		lap, err := parseLap(fields)
+		if err != nil {
+			return nil, err
+		}
+		laps = append(laps, lap)
+	}
+	return laps, nil
+}
+
+// parseDuration reads a Go duration, or a number of units
+func parseDuration(value interface{}, unit time.Duration) (time.Duration, error) {
+	switch v := value.(type) {
+	case string:
+		return time.ParseDuration(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return time.Duration(n) * unit, nil
+		}
+		units, err := v.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(units * float64(unit)), nil
+	default:
+		return 0, fmt.Errorf("cannot parse lap time %v", value)
+	}
+}
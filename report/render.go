@@ -0,0 +1,223 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alexus1024/stopwatch"
+)
+
+// Format is a way to render laps
+type Format string
+
+const (
+	// FormatTable renders a table with a duration and share of total time per lap
+	FormatTable Format = "table"
+	// FormatGantt renders a text Gantt chart showing when every lap ran
+	FormatGantt Format = "gantt"
+
+	ganttWidth = 50
+
+	adaptiveDecimals = 2
+
+	colorWarn     = "\x1b[33m" // yellow
+	colorCritical = "\x1b[31m" // red
+	colorReset    = "\x1b[0m"
+)
+
+// Render reads a serialized stopwatch from r and writes it to w in the given format
+func Render(r io.Reader, w io.Writer, format Format) error {
+	laps, err := Parse(r)
+	if err != nil {
+		return err
+	}
+	return RenderLaps(laps, w, format)
+}
+
+// RenderLaps writes laps to w in the given format
+func RenderLaps(laps []Lap, w io.Writer, format Format) error {
+	switch format {
+	case FormatTable:
+		return RenderTable(laps, w, TableOptions{})
+	case FormatGantt:
+		return renderGantt(laps, w)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func total(laps []Lap) time.Duration {
+	var sum time.Duration
+	for _, lap := range laps {
+		sum += lap.Duration
+	}
+	return sum
+}
+
+func share(part, whole time.Duration) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+// TableOptions tunes the table rendering
+type TableOptions struct {
+	// ByDuration sorts laps from the longest to the shortest,
+	// so the cumulative column shows how much time the top laps cover
+	ByDuration bool
+	// CutAt stops listing laps once the cumulative share reaches this percentage, e.g. 95.
+	// The rest is summarized in a single row. Zero lists all laps.
+	CutAt float64
+	// Adaptive picks the unit per lap by its magnitude (ns, µs, ms or s) and aligns values
+	// by the decimal point, so laps of very different lengths stay readable
+	Adaptive bool
+	// Color paints rows of laps with warn or critical severity in the lap data yellow and red
+	// with ANSI escape codes, see stopwatch.SetSeverityBands
+	Color bool
+}
+
+// RenderTable writes laps as a table with a share and a cumulative share of total time per lap
+func RenderTable(laps []Lap, w io.Writer, opts TableOptions) error {
+	sum := total(laps)
+
+	if opts.ByDuration {
+		sorted := make([]Lap, len(laps))
+		copy(sorted, laps)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+		laps = sorted
+	}
+
+	duration := func(d time.Duration) []string {
+		if !opts.Adaptive {
+			return []string{d.String()}
+		}
+		value, unit := stopwatch.AdaptiveUnit(d)
+		return []string{strconv.FormatFloat(value, 'f', adaptiveDecimals, 64), unit}
+	}
+	row := func(state string, d time.Duration, cells ...string) []string {
+		return append(append([]string{state}, duration(d)...), cells...)
+	}
+
+	header := []string{"STATE", "DURATION", "%", "CUM %"}
+	alignLeft := []bool{true, false, false, false}
+	if opts.Adaptive {
+		header = []string{"STATE", "DURATION", "", "%", "CUM %"}
+		alignLeft = []bool{true, false, true, false, false}
+	}
+
+	rows := [][]string{header}
+	colors := []string{""}
+	var cumulative time.Duration
+	for i, lap := range laps {
+		if opts.CutAt > 0 && share(cumulative, sum) >= opts.CutAt {
+			rest := sum - cumulative
+			rows = append(rows, row(fmt.Sprintf("(%d more)", len(laps)-i), rest, percent(share(rest, sum)), percent(100)))
+			break
+		}
+		cumulative += lap.Duration
+		rows = append(rows, row(lap.State, lap.Duration, percent(share(lap.Duration, sum)), percent(share(cumulative, sum))))
+		color := ""
+		if opts.Color {
+			color = severityColor(lap.Data[stopwatch.SeverityKey])
+		}
+		colors = append(colors, color)
+	}
+	rows = append(rows, row("TOTAL", sum, percent(100), ""))
+
+	return writeColoredRows(w, rows, alignLeft, colors)
+}
+
+func severityColor(severity interface{}) string {
+	switch fmt.Sprint(severity) {
+	case string(stopwatch.SeverityWarn):
+		return colorWarn
+	case string(stopwatch.SeverityCritical):
+		return colorCritical
+	default:
+		return ""
+	}
+}
+
+func percent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+// writeRows aligns columns to the left or to the right
+func writeRows(w io.Writer, rows [][]string, alignLeft []bool) error {
+	return writeColoredRows(w, rows, alignLeft, nil)
+}
+
+// writeColoredRows is writeRows painting rows with ANSI colors, rows without colors are left as is
+func writeColoredRows(w io.Writer, rows [][]string, alignLeft []bool, colors []string) error {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if alignLeft[i] {
+				cells[i] = cell + pad
+			} else {
+				cells[i] = pad + cell
+			}
+		}
+		line := strings.TrimRight(strings.Join(cells, "  "), " ")
+		if r < len(colors) && colors[r] != "" {
+			line = colors[r] + line + colorReset
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderGantt(laps []Lap, w io.Writer) error {
+	sum := total(laps)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	var offset time.Duration
+	for _, lap := range laps {
+		from := scale(offset, sum)
+		to := scale(offset+lap.Duration, sum)
+		if to < from {
+			to = from // negative laps get no bar
+		}
+		if to == from && to < ganttWidth {
+			to++ // show even the shortest laps
+		}
+		bar := strings.Repeat(" ", from) + strings.Repeat("#", to-from) + strings.Repeat(" ", ganttWidth-to)
+		fmt.Fprintf(tw, "%s\t|%s|\t%s\n", lap.State, bar, lap.Duration)
+		offset += lap.Duration
+	}
+	return tw.Flush()
+}
+
+// scale converts an offset into a position on the chart, clamped to its width
+func scale(offset, sum time.Duration) int {
+	if sum <= 0 {
+		return 0
+	}
+	position := int(float64(offset) / float64(sum) * ganttWidth)
+	switch {
+	case position < 0:
+		return 0
+	case position > ganttWidth:
+		return ganttWidth
+	}
+	return position
+}
@@ -0,0 +1,211 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArray(t *testing.T) {
+	laps, err := Parse(strings.NewReader(`[{"state":"db", "time":"10ms", "rows":"2"}, {"state":"render", "time":"1.5s"}]`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Lap{
+		{State: "db", Duration: 10 * time.Millisecond, Data: map[string]interface{}{"rows": "2"}},
+		{State: "render", Duration: 1500 * time.Millisecond},
+	}, laps)
+}
+
+func TestParseObject(t *testing.T) {
+	laps, err := Parse(strings.NewReader(`{"render":"10ms", "db":2.5}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Lap{
+		{State: "render", Duration: 10 * time.Millisecond},
+		{State: "db", Duration: 2500 * time.Microsecond},
+	}, laps)
+}
+
+func TestParseStopwatch(t *testing.T) {
+	sw := stopwatch.New(0, true)
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+
+	for _, mode := range []stopwatch.FormattingMode{
+		stopwatch.FormattingModeJsonArray,
+		stopwatch.FormattingModeJsonSimpleObject,
+		stopwatch.FormattingModeJsonMsObject,
+		stopwatch.FormattingModeJsonDetailed,
+		stopwatch.FormattingModeNDJSON,
+		stopwatch.FormattingModeJsonFull,
+		stopwatch.FormattingModeGoogleCloud,
+	} {
+		sw.SetFormattingMode(mode)
+		laps, err := Parse(strings.NewReader(sw.String()))
+		assert.NoError(t, err, mode)
+		if assert.Len(t, laps, 2, mode) {
+			assert.Equal(t, "lap2", laps[1].State, mode)
+		}
+	}
+
+	for _, mode := range []stopwatch.FormattingMode{
+		stopwatch.FormattingModeECS,
+		stopwatch.FormattingModeCloudWatchEMF,
+		stopwatch.FormattingModeFlat,
+		stopwatch.FormattingModeJsonSummary,
+	} {
+		sw.SetFormattingMode(mode)
+		_, err := Parse(strings.NewReader(sw.String()))
+		assert.True(t, errors.Is(err, ErrUnsupportedMode), mode)
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	sw := stopwatch.New(0, true)
+	sw.SetFormattingMode(stopwatch.FormattingModeJsonIntObject)
+	sw.LapWithDataAndTime(time.Now().Add(2137*time.Microsecond), "db", nil)
+
+	laps, err := ParseUnit(strings.NewReader(sw.String()), time.Microsecond)
+	assert.NoError(t, err)
+	if assert.Len(t, laps, 1) {
+		assert.InDelta(t, 2137*time.Microsecond, laps[0].Duration, float64(time.Millisecond))
+	}
+
+	laps, err = ParseUnit(strings.NewReader(`{"db":2.5, "render":3}`), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []Lap{{State: "db", Duration: 2500 * time.Millisecond}, {State: "render", Duration: 3 * time.Second}}, laps)
+
+	laps, err = ParseUnit(strings.NewReader(`[{"state":"db","ms":2.5,"offset_ms":0}]`), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []Lap{{State: "db", Duration: 2500 * time.Microsecond}}, laps, "detailed laps are in milliseconds")
+
+	_, err = ParseUnit(strings.NewReader(`{"db":1}`), 0)
+	assert.Error(t, err)
+}
+
+func TestParseNDJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	sw := stopwatch.New(0, true)
+	sw.AddSink(stopwatch.NewNDJSONSink(&buf))
+	sw.LapWithData("db", map[string]interface{}{"rows": 2})
+	sw.Lap("render")
+
+	laps, err := Parse(&buf)
+	assert.NoError(t, err)
+	if assert.Len(t, laps, 2) {
+		assert.Equal(t, "db", laps[0].State)
+		assert.Equal(t, json.Number("2"), laps[0].Data["rows"])
+		assert.Equal(t, "render", laps[1].State)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(`"lap"`))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader(`{"lap":"soon"}`))
+	assert.Error(t, err)
+}
+
+func TestRenderTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(strings.NewReader(`{"db":"30ms", "render":"10ms"}`), &buf, FormatTable)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ""+
+		"STATE   DURATION       %   CUM %\n"+
+		"db          30ms   75.0%   75.0%\n"+
+		"render      10ms   25.0%  100.0%\n"+
+		"TOTAL       40ms  100.0%\n", buf.String())
+}
+
+func TestRenderTableCut(t *testing.T) {
+	laps := []Lap{
+		{State: "parse", Duration: 5 * time.Millisecond},
+		{State: "db", Duration: 80 * time.Millisecond},
+		{State: "render", Duration: 10 * time.Millisecond},
+		{State: "log", Duration: 5 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	err := RenderTable(laps, &buf, TableOptions{ByDuration: true, CutAt: 90})
+	assert.NoError(t, err)
+
+	assert.Equal(t, ""+
+		"STATE     DURATION       %   CUM %\n"+
+		"db            80ms   80.0%   80.0%\n"+
+		"render        10ms   10.0%   90.0%\n"+
+		"(2 more)      10ms   10.0%  100.0%\n"+
+		"TOTAL        100ms  100.0%\n", buf.String())
+}
+
+func TestRenderTableAdaptive(t *testing.T) {
+	laps := []Lap{
+		{State: "parse", Duration: 3 * time.Nanosecond},
+		{State: "job", Duration: 45 * time.Minute},
+		{State: "db", Duration: 12300 * time.Microsecond},
+	}
+
+	var buf bytes.Buffer
+	err := RenderTable(laps, &buf, TableOptions{Adaptive: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, ""+
+		"STATE  DURATION           %   CUM %\n"+
+		"parse      3.00  ns    0.0%    0.0%\n"+
+		"job     2700.00  s   100.0%  100.0%\n"+
+		"db        12.30  ms    0.0%  100.0%\n"+
+		"TOTAL   2700.01  s   100.0%\n", buf.String())
+}
+
+func TestRenderGantt(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(strings.NewReader(`{"db":"30ms", "render":"10ms"}`), &buf, FormatGantt)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "db     |"+strings.Repeat("#", 37)+strings.Repeat(" ", 13)+"| 30ms", lines[0])
+	assert.Equal(t, "render |"+strings.Repeat(" ", 37)+strings.Repeat("#", 13)+"| 10ms", lines[1])
+}
+
+func TestRenderGanttNegativeAndZero(t *testing.T) {
+	for _, input := range []string{`{"a":10, "b":-5}`, `{"a":0, "b":0}`, `{"a":-5}`, `{"a":10, "b":0, "c":-20}`} {
+		var buf bytes.Buffer
+		assert.NotPanics(t, func() {
+			assert.NoError(t, Render(strings.NewReader(input), &buf, FormatGantt), input)
+		}, input)
+		for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+			bar := line[strings.Index(line, "|")+1 : strings.LastIndex(line, "|")]
+			assert.Len(t, bar, 50, input)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	err := RenderLaps(nil, &bytes.Buffer{}, "pie")
+	assert.Error(t, err)
+}
+
+func TestRenderTableColor(t *testing.T) {
+	laps := []Lap{
+		{State: "db", Duration: 30 * time.Millisecond, Data: map[string]interface{}{stopwatch.SeverityKey: "critical"}},
+		{State: "cache", Duration: 10 * time.Millisecond, Data: map[string]interface{}{stopwatch.SeverityKey: "warn"}},
+		{State: "render", Duration: 10 * time.Millisecond, Data: map[string]interface{}{stopwatch.SeverityKey: "ok"}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, RenderTable(laps, &buf, TableOptions{Color: true}))
+	assert.Equal(t, ""+
+		"STATE   DURATION       %   CUM %\n"+
+		"\x1b[31mdb          30ms   60.0%   60.0%\x1b[0m\n"+
+		"\x1b[33mcache       10ms   20.0%   80.0%\x1b[0m\n"+
+		"render      10ms   20.0%  100.0%\n"+
+		"TOTAL       50ms  100.0%\n", buf.String())
+}
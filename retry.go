@@ -0,0 +1,84 @@
+package stopwatch
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures retries of exporters. The zero value makes a single attempt.
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, 1 if zero
+	Attempts int
+	// Backoff is the wait before the second attempt, doubled for every next one
+	Backoff time.Duration
+	// MaxBackoff caps the wait, no cap if zero
+	MaxBackoff time.Duration
+	// Jitter randomizes the wait by up to this fraction of it, e.g. 0.2 for ±20%
+	Jitter float64
+	// Retryable classifies errors, IsRetryable if nil
+	Retryable func(err error) bool
+}
+
+// StatusError is returned by exporters when the collector responds with an error status
+type StatusError struct {
+	// Service is the name of the collector, like "honeycomb"
+	Service    string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return e.Service + " responded with " + e.Status
+}
+
+// IsRetryable reports whether a request may succeed if repeated:
+// it failed with 429 or 5xx status, or with a network error, but not because ctx is done
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+	}
+	return true
+}
+
+// do calls fn until it succeeds, fails with a not retryable error, or attempts are over
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.Attempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.jitter(backoff))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+func (p RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	return d + time.Duration((rand.Float64()*2-1)*p.Jitter*float64(d))
+}
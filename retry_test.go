@@ -0,0 +1,103 @@
+package stopwatch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(context.Canceled))
+	assert.True(t, IsRetryable(errors.New("connection refused")))
+	assert.True(t, IsRetryable(&StatusError{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, IsRetryable(&StatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, IsRetryable(&StatusError{StatusCode: http.StatusBadRequest}))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	calls := 0
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+	err := policy.do(context.Background(), func() error {
+		calls++
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = policy.do(context.Background(), func() error {
+		calls++
+		return &StatusError{Service: "test", StatusCode: http.StatusBadRequest, Status: "400 Bad Request"}
+	})
+	assert.EqualError(t, err, "test responded with 400 Bad Request")
+	assert.Equal(t, 1, calls, "not retryable")
+
+	calls = 0
+	policy.Retryable = func(err error) bool { return true }
+	err = policy.do(context.Background(), func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{Backoff: 10 * time.Millisecond, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		d := policy.jitter(policy.Backoff)
+		assert.True(t, d >= 8*time.Millisecond && d <= 12*time.Millisecond, d)
+	}
+
+	var waits []time.Duration
+	last := time.Now()
+	policy = RetryPolicy{Attempts: 4, Backoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	_ = policy.do(context.Background(), func() error {
+		waits = append(waits, time.Since(last))
+		last = time.Now()
+		return errors.New("failed")
+	})
+	assert.Len(t, waits, 4)
+	assert.True(t, waits[1] >= 5*time.Millisecond)
+	assert.True(t, waits[2] >= 10*time.Millisecond)
+	assert.True(t, waits[3] >= 10*time.Millisecond)
+}
+
+func TestRetryPolicyStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := RetryPolicy{Attempts: 5, Backoff: time.Hour}.do(ctx, func() error {
+		calls++
+		cancel()
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, 1, calls)
+}
+
+func TestDatadogExporterRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	sw := New(0, true)
+	sw.Lap("lap1")
+
+	exporter := NewDatadogExporter(DatadogConfig{AgentURL: server.URL, Retry: RetryPolicy{Attempts: 2}})
+	assert.NoError(t, exporter.Export(context.Background(), sw))
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,83 @@
+package stopwatch
+
+import (
+	"strings"
+	"time"
+)
+
+// Rollup is the total time of a level of dotted lap states: "fetch" rolls up
+// laps "fetch", "fetch.db" and "fetch.db.query"
+type Rollup struct {
+	State string
+	// Depth is 0 for top level states, 1 for "fetch.db", and so on
+	Depth int
+	Total time.Duration
+	Laps  int
+}
+
+// Rollups returns totals of every level of dotted lap states, parents before their children,
+// siblings in order of their first lap, so related laps aggregate without child stopwatches
+func (s *Stopwatch) Rollups() []Rollup {
+	s.rlock()
+	defer s.runlock()
+	return rollups(s.laps)
+}
+
+type rollupNode struct {
+	rollup   Rollup
+	children []*rollupNode
+	index    map[string]*rollupNode
+}
+
+func (n *rollupNode) child(state string, depth int) *rollupNode {
+	if child, found := n.index[state]; found {
+		return child
+	}
+	if n.index == nil {
+		n.index = make(map[string]*rollupNode)
+	}
+	child := &rollupNode{rollup: Rollup{State: state, Depth: depth}}
+	n.index[state] = child
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *rollupNode) flatten(result []Rollup) []Rollup {
+	for _, child := range n.children {
+		result = append(result, child.rollup)
+		result = child.flatten(result)
+	}
+	return result
+}
+
+func rollups(laps []Lap) []Rollup {
+	var root rollupNode
+	for _, lap := range laps {
+		node := &root
+		frames := strings.Split(lap.state, ".")
+		for depth := range frames {
+			node = node.child(strings.Join(frames[:depth+1], "."), depth)
+			node.rollup.Total += lap.duration
+			node.rollup.Laps++
+		}
+	}
+	return root.flatten(nil)
+}
+
+// rollupsMs returns rollup totals in milliseconds, nil without dotted states
+func rollupsMs(laps []Lap) map[string]float64 {
+	var result map[string]float64
+	for _, lap := range laps {
+		if strings.Contains(lap.state, ".") {
+			result = make(map[string]float64)
+			break
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	for _, rollup := range rollups(laps) {
+		result[rollup.State] = milliseconds(rollup.Total)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollups(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{
+		{state: "fetch.db.query", duration: 10 * time.Millisecond},
+		{state: "parse", duration: 5 * time.Millisecond},
+		{state: "fetch.cache", duration: 2 * time.Millisecond},
+		{state: "fetch.db.query", duration: 20 * time.Millisecond},
+		{state: "fetch", duration: time.Millisecond},
+	}
+
+	assert.Equal(t, []Rollup{
+		{State: "fetch", Depth: 0, Total: 33 * time.Millisecond, Laps: 4},
+		{State: "fetch.db", Depth: 1, Total: 30 * time.Millisecond, Laps: 2},
+		{State: "fetch.db.query", Depth: 2, Total: 30 * time.Millisecond, Laps: 2},
+		{State: "fetch.cache", Depth: 1, Total: 2 * time.Millisecond, Laps: 1},
+		{State: "parse", Depth: 0, Total: 5 * time.Millisecond, Laps: 1},
+	}, sw.Rollups())
+
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	var full FullStopwatch
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	assert.Equal(t, 33.0, full.RollupsMs["fetch"])
+	assert.Equal(t, 30.0, full.RollupsMs["fetch.db"])
+	assert.Equal(t, 5.0, full.RollupsMs["parse"])
+}
+
+func TestRollupsWithoutDots(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{{state: "parse", duration: time.Millisecond}}
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	assert.NotContains(t, sw.String(), "rollups_ms")
+}
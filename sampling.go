@@ -0,0 +1,73 @@
+package stopwatch
+
+import (
+	"math/rand"
+	"time"
+)
+
+// StateCount is the number and total duration of laps with a state
+type StateCount struct {
+	Count int
+	Total time.Duration
+}
+
+// SetSampling makes the stopwatch keep only a fraction of laps, chosen at random,
+// e.g. 0.01 keeps one lap of a hundred. Not kept laps are not passed to sinks either,
+// but they are counted, see StateCounts. Rate 1 or more keeps all laps, the default,
+// rate 0 or less keeps none, so only the counts are recorded.
+func (s *Stopwatch) SetSampling(rate float64) {
+	s.lock()
+	defer s.unlock()
+	switch {
+	case rate >= 1:
+		s.skipRate = 0
+	case rate <= 0:
+		s.skipRate = 1
+	default:
+		s.skipRate = 1 - rate
+	}
+}
+
+// StateCounts returns the number and total duration of laps per state since the last Reset.
+// Laps not kept because of sampling or the MaxLaps limit are counted too.
+func (s *Stopwatch) StateCounts() map[string]StateCount {
+	s.rlock()
+	defer s.runlock()
+	counts := make(map[string]StateCount, len(s.counts))
+	for state, stats := range s.counts {
+		counts[state] = StateCount{Count: stats.count, Total: stats.total}
+	}
+	return counts
+}
+
+// stateStats are updated with every lap, see StateCounts and Summary
+type stateStats struct {
+	count           int
+	total, min, max time.Duration
+}
+
+// countLap must be called under the lock
+func (s *Stopwatch) countLap(lap Lap) {
+	if s.counts == nil {
+		s.counts = make(map[string]stateStats)
+	}
+	stats := s.counts[lap.state]
+	stats.add(lap.duration)
+	s.counts[lap.state] = stats
+}
+
+func (s *stateStats) add(d time.Duration) {
+	if s.count == 0 || d < s.min {
+		s.min = d
+	}
+	if s.count == 0 || d > s.max {
+		s.max = d
+	}
+	s.count++
+	s.total += d
+}
+
+// sampled decides whether to keep the lap
+func (s *Stopwatch) sampled() bool {
+	return s.skipRate <= 0 || rand.Float64() >= s.skipRate
+}
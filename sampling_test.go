@@ -0,0 +1,59 @@
+package stopwatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampling(t *testing.T) {
+	sw := New(0, true)
+	sw.SetSampling(0.1)
+
+	sinkCalls := 0
+	sw.AddSink(SinkFunc(func(lap Lap) error {
+		sinkCalls++
+		return nil
+	}))
+
+	for i := 0; i < 1000; i++ {
+		sw.Lap("lap")
+	}
+
+	kept := len(sw.Laps())
+	assert.True(t, kept > 30 && kept < 300, "kept %d laps", kept)
+	assert.Equal(t, kept, sinkCalls)
+	assert.Equal(t, 1000, sw.StateCounts()["lap"].Count)
+}
+
+func TestSamplingBounds(t *testing.T) {
+	sw := New(0, true)
+	sw.SetSampling(0)
+	for i := 0; i < 100; i++ {
+		sw.Lap("lap")
+	}
+	assert.Empty(t, sw.Laps())
+	assert.Equal(t, 100, sw.StateCounts()["lap"].Count)
+
+	sw.SetSampling(1)
+	for i := 0; i < 100; i++ {
+		sw.Lap("lap")
+	}
+	assert.Len(t, sw.Laps(), 100)
+}
+
+func TestStateCounts(t *testing.T) {
+	sw := New(0, true)
+	sw.SetMaxLaps(1)
+	sw.Lap("db")
+	sw.Lap("render")
+	sw.Lap("db")
+
+	counts := sw.StateCounts()
+	assert.Equal(t, 2, counts["db"].Count)
+	assert.Equal(t, 1, counts["render"].Count)
+	assert.Len(t, sw.Laps(), 1)
+
+	sw.Reset(0, true)
+	assert.Empty(t, sw.StateCounts())
+}
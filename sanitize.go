@@ -0,0 +1,57 @@
+package stopwatch
+
+import "strings"
+
+// RedactedValue replaces values of keys set by RedactKeys
+const RedactedValue = "[REDACTED]"
+
+// Sanitizer is called for every lap data entry before the lap is stored, logged or exported.
+// It returns the value to keep, e.g. masked, or false to drop the entry.
+type Sanitizer func(key string, value interface{}) (interface{}, bool)
+
+// SetSanitizer sets a sanitizer of lap data, so secrets or PII put into lap data by mistake
+// can be masked or dropped in one place. Nil turns it off.
+func (s *Stopwatch) SetSanitizer(sanitizer Sanitizer) {
+	s.lock()
+	defer s.unlock()
+	s.sanitizer = sanitizer
+}
+
+// RedactKeys makes lap data values of the keys, compared case-insensitively, replaced
+// with RedactedValue before the lap is stored, logged or exported, so they never reach
+// any formatting mode or sink. Keys are added to those of previous calls.
+func (s *Stopwatch) RedactKeys(keys ...string) {
+	s.lock()
+	defer s.unlock()
+	if s.redacted == nil {
+		s.redacted = make(map[string]struct{}, len(keys))
+	}
+	for _, key := range keys {
+		s.redacted[strings.ToLower(key)] = struct{}{}
+	}
+}
+
+// sanitizeData must be called under the lock, the data map is copied, never modified
+func (s *Stopwatch) sanitizeData(data map[string]interface{}) map[string]interface{} {
+	if s.sanitizer == nil && len(s.redacted) == 0 || len(data) == 0 {
+		return data
+	}
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if _, found := s.redacted[strings.ToLower(k)]; found {
+			result[k] = RedactedValue
+			continue
+		}
+		if s.sanitizer == nil {
+			result[k] = v
+			continue
+		}
+		if v, ok := s.sanitizer(k, v); ok {
+			result[k] = v
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
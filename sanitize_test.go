@@ -0,0 +1,52 @@
+package stopwatch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizer(t *testing.T) {
+	var events []Event
+	sw := New(0, true)
+	sw.SetEventLog(func(e Event) { events = append(events, e) })
+	sw.SetSanitizer(func(key string, value interface{}) (interface{}, bool) {
+		switch key {
+		case "password":
+			return nil, false
+		case "email":
+			return "***", true
+		}
+		return value, true
+	})
+
+	data := map[string]interface{}{"password": "secret", "email": "a@b.c", "rows": 2}
+	lap := sw.LapWithData("login", data)
+
+	assert.Equal(t, map[string]interface{}{"email": "***", "rows": 2}, lap.data)
+	assert.Equal(t, lap.data, events[len(events)-1].Data)
+	assert.Equal(t, "secret", data["password"], "caller's data is not modified")
+
+	assert.Nil(t, sw.LapWithData("login", map[string]interface{}{"password": "secret"}).data)
+
+	sw.SetSanitizer(nil)
+	assert.Equal(t, "secret", sw.LapWithData("login", data).data["password"])
+}
+
+func TestRedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.AddSink(NewNDJSONSink(&buf))
+	sw.RedactKeys("authorization")
+	sw.RedactKeys("Password")
+
+	data := map[string]interface{}{"Authorization": "Bearer xyz", "password": "secret", "rows": 2}
+	lap := sw.LapWithData("login", data)
+
+	assert.Equal(t, map[string]interface{}{"Authorization": RedactedValue, "password": RedactedValue, "rows": 2}, lap.data)
+	assert.NotContains(t, buf.String(), "secret")
+	assert.NotContains(t, buf.String(), "xyz")
+	assert.NotContains(t, sw.String(), "secret")
+	assert.Equal(t, "secret", data["password"], "caller's data is not modified")
+}
@@ -0,0 +1,69 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// SelfMetrics are counters of the stopwatch package itself, summed over all stopwatches
+// since the process start, to verify the instrumentation isn't lying or overloaded
+type SelfMetrics struct {
+	// LapsRecorded counts laps of enabled stopwatches, kept or not
+	LapsRecorded uint64 `json:"laps_recorded"`
+	// LapsDropped counts laps not kept because of sampling or the rate limit,
+	// and laps dropped by AsyncSink queues
+	LapsDropped uint64 `json:"laps_dropped"`
+	// ExportFailures counts errors returned by sinks and exporters
+	ExportFailures uint64 `json:"export_failures"`
+	// FormattingTime is the time spent rendering stopwatches, see String and MarshalJSON
+	FormattingTime time.Duration `json:"formatting_time_ns"`
+}
+
+var selfMetrics struct {
+	lapsRecorded   uint64
+	lapsDropped    uint64
+	exportFailures uint64
+	formattingNs   int64
+}
+
+// ReadSelfMetrics returns the current values of self-metrics
+func ReadSelfMetrics() SelfMetrics {
+	return SelfMetrics{
+		LapsRecorded:   atomic.LoadUint64(&selfMetrics.lapsRecorded),
+		LapsDropped:    atomic.LoadUint64(&selfMetrics.lapsDropped),
+		ExportFailures: atomic.LoadUint64(&selfMetrics.exportFailures),
+		FormattingTime: time.Duration(atomic.LoadInt64(&selfMetrics.formattingNs)),
+	}
+}
+
+// SelfMetricsHandler serves self-metrics as JSON, mount it next to other debug handlers:
+//
+//	http.Handle("/debug/stopwatch", stopwatch.SelfMetricsHandler())
+func SelfMetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ReadSelfMetrics())
+	})
+}
+
+func countLapRecorded() {
+	atomic.AddUint64(&selfMetrics.lapsRecorded, 1)
+}
+
+func countLapsDropped(n int) {
+	atomic.AddUint64(&selfMetrics.lapsDropped, uint64(n))
+}
+
+// countExport counts the error, if any, and returns it
+func countExport(err error) error {
+	if err != nil {
+		atomic.AddUint64(&selfMetrics.exportFailures, 1)
+	}
+	return err
+}
+
+func countFormatting(since time.Time) {
+	atomic.AddInt64(&selfMetrics.formattingNs, int64(time.Since(since)))
+}
@@ -0,0 +1,38 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfMetrics(t *testing.T) {
+	before := ReadSelfMetrics()
+
+	sw := New(0, true)
+	sw.AddSink(SinkFunc(func(lap Lap) error { return errors.New("failed") }))
+	sw.SetRateLimit(1)
+	sw.Lap("lap1")
+	sw.Lap("lap1")
+	_ = sw.String()
+
+	after := ReadSelfMetrics()
+	assert.True(t, after.LapsRecorded-before.LapsRecorded >= 2)
+	assert.True(t, after.LapsDropped-before.LapsDropped >= 1)
+	assert.True(t, after.ExportFailures-before.ExportFailures >= 1)
+	assert.True(t, after.FormattingTime > before.FormattingTime)
+}
+
+func TestSelfMetricsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SelfMetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/stopwatch", nil))
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var metrics map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metrics))
+	assert.Contains(t, metrics, "laps_recorded")
+	assert.Contains(t, metrics, "formatting_time_ns")
+}
@@ -0,0 +1,73 @@
+package stopwatch
+
+import (
+	"time"
+)
+
+// SentryBreadcrumb mirrors the fields of a Sentry breadcrumb, so it converts
+// into sentry.Breadcrumb field by field without this package depending on the SDK
+type SentryBreadcrumb struct {
+	Type      string
+	Category  string
+	Message   string
+	Data      map[string]interface{}
+	Timestamp time.Time
+}
+
+// SentryContext returns the lap breakdown to attach to captured errors, so slow-request
+// errors come with their phase timings:
+//
+//	sentry.WithScope(func(scope *sentry.Scope) {
+//		scope.SetContext("stopwatch", sw.SentryContext())
+//		sentry.CaptureException(err)
+//	})
+func (s *Stopwatch) SentryContext() map[string]interface{} {
+	s.rlock()
+	defer s.runlock()
+
+	laps := make([]map[string]interface{}, len(s.laps))
+	for i, lap := range s.laps {
+		item := map[string]interface{}{
+			"state":     lap.state,
+			"ms":        milliseconds(lap.duration),
+			"offset_ms": milliseconds(lap.offset),
+		}
+		if len(lap.data) > 0 {
+			item["data"] = lap.data
+		}
+		laps[i] = item
+	}
+
+	context := map[string]interface{}{
+		"running":    s.active(),
+		"elapsed_ms": milliseconds(s.ElapsedTime()),
+		"laps":       laps,
+	}
+	if s.correlationID != "" {
+		context[CorrelationIDKey] = s.correlationID
+	}
+	if s.runID != "" {
+		context[RunIDKey] = s.runID
+	}
+	return context
+}
+
+// SentryBreadcrumbs returns a breadcrumb per lap, recorded at the moment the lap finished
+func (s *Stopwatch) SentryBreadcrumbs() []SentryBreadcrumb {
+	s.rlock()
+	defer s.runlock()
+
+	breadcrumbs := make([]SentryBreadcrumb, len(s.laps))
+	for i, lap := range s.laps {
+		data := copyData(lap.data, 1)
+		data["duration_ms"] = milliseconds(lap.duration)
+		breadcrumbs[i] = SentryBreadcrumb{
+			Type:      "default",
+			Category:  "stopwatch",
+			Message:   lap.state,
+			Data:      data,
+			Timestamp: lap.end,
+		}
+	}
+	return breadcrumbs
+}
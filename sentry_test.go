@@ -0,0 +1,42 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentryContext(t *testing.T) {
+	sw := New(0, false)
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{
+		{state: "db", duration: 1500 * time.Microsecond, data: map[string]interface{}{"rows": 2}},
+		{state: "render", offset: 1500 * time.Microsecond, duration: time.Millisecond},
+	}
+
+	context := sw.SentryContext()
+	assert.Equal(t, false, context["running"])
+	assert.Equal(t, "req-1", context[CorrelationIDKey])
+	assert.Equal(t, []map[string]interface{}{
+		{"state": "db", "ms": 1.5, "offset_ms": 0.0, "data": map[string]interface{}{"rows": 2}},
+		{"state": "render", "ms": 1.0, "offset_ms": 1.5},
+	}, context["laps"])
+}
+
+func TestSentryBreadcrumbs(t *testing.T) {
+	end := time.Now()
+	sw := New(0, false)
+	sw.laps = []Lap{
+		{state: "db", end: end, duration: 1500 * time.Microsecond, data: map[string]interface{}{"rows": 2}},
+	}
+
+	assert.Equal(t, []SentryBreadcrumb{{
+		Type:      "default",
+		Category:  "stopwatch",
+		Message:   "db",
+		Data:      map[string]interface{}{"rows": 2, "duration_ms": 1.5},
+		Timestamp: end,
+	}}, sw.SentryBreadcrumbs())
+	assert.Len(t, sw.laps[0].data, 1, "lap data must not be modified")
+}
@@ -0,0 +1,46 @@
+package stopwatch
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// RunIDKey is the field holding the run ID in formatting modes and exports
+const RunIDKey = "run_id"
+
+// RunID returns the run ID given to laps. New and Reset generate one with NewRunID,
+// so laps streamed from the same run can be stitched together downstream.
+func (s *Stopwatch) RunID() string {
+	s.rlock()
+	defer s.runlock()
+	return s.runID
+}
+
+// SetRunID replaces the run ID given to laps until the next Reset, see Lap.RunID.
+// It's in the detailed formatting modes, sinks and exporters, so laps streamed from many runs
+// can be told apart. The array and object modes leave it out.
+func (s *Stopwatch) SetRunID(id string) {
+	s.lock()
+	defer s.unlock()
+	s.runID = id
+}
+
+// NewRunID generates a random UUID (version 4), see RunID
+func NewRunID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixNano()))
+		binary.BigEndian.PutUint64(b[8:], randomID())
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// nextSeq must be called under the lock
+func (s *Stopwatch) nextSeq() uint64 {
+	s.seq++
+	return s.seq
+}
@@ -0,0 +1,58 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLapSeq(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.AddSink(NewNDJSONSink(&buf))
+	sw.SetRunID("run-1")
+
+	assert.Equal(t, uint64(1), sw.Lap("a").Seq())
+	lap := sw.Lap("b")
+	assert.Equal(t, uint64(2), lap.Seq())
+	assert.Equal(t, "run-1", lap.RunID())
+
+	var streamed []DetailedLap
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var lap DetailedLap
+		assert.NoError(t, dec.Decode(&lap))
+		streamed = append(streamed, lap)
+	}
+	if assert.Len(t, streamed, 2) {
+		assert.Equal(t, uint64(1), streamed[0].Seq)
+		assert.Equal(t, uint64(2), streamed[1].Seq)
+		assert.Equal(t, "run-1", streamed[1].RunID)
+	}
+
+	sw.Reset(0, true)
+	assert.Equal(t, uint64(1), sw.Lap("c").Seq())
+}
+
+func TestNewRunID(t *testing.T) {
+	id := NewRunID()
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+	assert.NotEqual(t, id, NewRunID())
+}
+
+func TestGeneratedRunID(t *testing.T) {
+	sw := New(0, true)
+	first := sw.RunID()
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, first)
+	assert.Equal(t, first, sw.Lap("db").RunID())
+	assert.Equal(t, first, sw.Child("cache").RunID())
+
+	sw.Reset(0, true)
+	assert.NotEqual(t, first, sw.RunID())
+
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	assert.Contains(t, sw.String(), `"run_id":"`+sw.RunID()+`"`)
+}
@@ -0,0 +1,59 @@
+package stopwatch
+
+import "time"
+
+// SeverityKey is the lap data key holding the Severity of laps with bands, see SetSeverityBands
+const SeverityKey = "severity"
+
+// Severity classifies a lap duration by the bands of its state
+type Severity string
+
+const (
+	SeverityOK       Severity = "ok"
+	SeverityWarn     Severity = "warn"
+	SeverityCritical Severity = "critical"
+)
+
+// SeverityBands are the lowest durations of a lap considered warn and critical,
+// zero leaves a band out
+type SeverityBands struct {
+	Warn, Critical time.Duration
+}
+
+// Classify returns the severity of the duration
+func (b SeverityBands) Classify(d time.Duration) Severity {
+	switch {
+	case b.Critical > 0 && d >= b.Critical:
+		return SeverityCritical
+	case b.Warn > 0 && d >= b.Warn:
+		return SeverityWarn
+	default:
+		return SeverityOK
+	}
+}
+
+// SetSeverityBands sets bands per lap state. Laps of these states get their Severity
+// in SeverityKey data, so every output shows phases out of line, and report tables
+// can color them. Nil turns it off.
+func (s *Stopwatch) SetSeverityBands(bands map[string]SeverityBands) {
+	s.lock()
+	defer s.unlock()
+	s.severityBands = bands
+}
+
+// Severity returns the severity of the lap, empty if its state had no bands
+func (l Lap) Severity() Severity {
+	severity, _ := l.data[SeverityKey].(string)
+	return Severity(severity)
+}
+
+// classifyLap must be called under the lock
+func (s *Stopwatch) classifyLap(state string, duration time.Duration, data map[string]interface{}) map[string]interface{} {
+	bands, found := s.severityBands[state]
+	if !found {
+		return data
+	}
+	data = copyData(data, 1)
+	data[SeverityKey] = string(bands.Classify(duration))
+	return data
+}
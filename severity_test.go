@@ -0,0 +1,29 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityBands(t *testing.T) {
+	bands := SeverityBands{Warn: 10 * time.Millisecond, Critical: 50 * time.Millisecond}
+	assert.Equal(t, SeverityOK, bands.Classify(9*time.Millisecond))
+	assert.Equal(t, SeverityWarn, bands.Classify(10*time.Millisecond))
+	assert.Equal(t, SeverityCritical, bands.Classify(time.Second))
+	assert.Equal(t, SeverityWarn, SeverityBands{Warn: time.Millisecond}.Classify(time.Hour))
+
+	sw := New(0, true)
+	sw.SetSeverityBands(map[string]SeverityBands{"db": bands})
+	sw.start = sw.start.Add(-20 * time.Millisecond)
+
+	db := sw.Lap("db")
+	assert.Equal(t, SeverityWarn, db.Severity())
+	assert.Equal(t, "warn", db.data[SeverityKey])
+	assert.Equal(t, SeverityOK, sw.Lap("db").Severity())
+	assert.Equal(t, Severity(""), sw.Lap("render").Severity())
+	assert.Contains(t, db.String(), `"severity":"warn"`)
+
+	assert.Equal(t, SeverityCritical, Lap{data: map[string]interface{}{SeverityKey: "critical"}}.Severity())
+}
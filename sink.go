@@ -0,0 +1,133 @@
+package stopwatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrClosed is returned by sinks and exporters used after Close
+var ErrClosed = errors.New("stopwatch: closed")
+
+// Sink receives every lap as soon as it is recorded.
+// Sinks are called outside of the stopwatch lock, possibly from several goroutines at once.
+type Sink interface {
+	WriteLap(lap Lap) error
+}
+
+// AddSink attaches a sink to the stopwatch. Errors returned by sinks are ignored,
+// a sink is responsible for reporting its own failures.
+func (s *Stopwatch) AddSink(sink Sink) {
+	s.lock()
+	defer s.unlock()
+	s.sinks = append(s.sinks, sink)
+}
+
+// OnLap subscribes fn to every recorded lap, like AddSink. Subscribers are called
+// in order of subscription, outside of the stopwatch lock, so they may read the stopwatch.
+func (s *Stopwatch) OnLap(fn func(Lap)) {
+	s.AddSink(SinkFunc(func(lap Lap) error {
+		fn(lap)
+		return nil
+	}))
+}
+
+// SinkCloser is implemented by sinks holding pending laps or other resources.
+// Close drains pending laps until ctx is done, the sink returns ErrClosed afterwards.
+type SinkCloser interface {
+	Close(ctx context.Context) error
+}
+
+// Close stops the stopwatch, detaches its sinks and closes those implementing SinkCloser,
+// so the tail of laps is not lost on shutdown. It closes the write-ahead log too, see OpenWAL,
+// and stops the watchdog, see SetWatchdog.
+// The first error is returned.
+func (s *Stopwatch) Close(ctx context.Context) error {
+	s.Stop()
+
+	s.lock()
+	sinks := s.sinks
+	s.sinks = nil
+	s.budgetWatch = nil
+	watchdog := s.watchdog
+	s.watchdog = nil
+	wal := s.wal
+	s.wal = nil
+	s.unlock()
+
+	if watchdog != nil {
+		watchdog.stop()
+	}
+	var first error
+	if wal != nil {
+		first = wal.file.Close()
+	}
+	for _, sink := range sinks {
+		closer, ok := sink.(SinkCloser)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(ctx); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(lap Lap) error
+
+// WriteLap calls f(lap)
+func (f SinkFunc) WriteLap(lap Lap) error {
+	return f(lap)
+}
+
+type ndjsonSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	enc    *json.Encoder
+	closed bool
+}
+
+// NewNDJSONSink creates a sink writing every lap to w as a single JSON line,
+// in the same form as FormattingModeJsonDetailed uses for laps.
+// Flushing or closing the sink flushes w if it is buffered, like bufio.Writer, but doesn't close it.
+// See AsyncOptions to flush it in batches.
+func NewNDJSONSink(w io.Writer) Sink {
+	return &ndjsonSink{w: w, enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonSink) WriteLap(lap Lap) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return ErrClosed
+	}
+	return n.enc.Encode(newDetailedLap(lap))
+}
+
+// Flush flushes w if it is buffered
+func (n *ndjsonSink) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.flushLocked()
+}
+
+func (n *ndjsonSink) flushLocked() error {
+	if f, ok := n.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (n *ndjsonSink) Close(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return nil
+	}
+	n.closed = true
+	return n.flushLocked()
+}
@@ -0,0 +1,124 @@
+package stopwatch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.AddSink(NewNDJSONSink(&buf))
+
+	sw.Lap("lap1")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	sw.LapWithData("lap2", map[string]interface{}{"rows": 2})
+
+	var states []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var lap DetailedLap
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &lap))
+		states = append(states, lap.State)
+	}
+	assert.Equal(t, []string{"lap1", "lap2"}, states)
+}
+
+func TestSinkMayReadStopwatch(t *testing.T) {
+	sw := New(0, true)
+	var counts []int
+	sw.AddSink(SinkFunc(func(lap Lap) error {
+		counts = append(counts, len(sw.Laps())) // would deadlock if called under the lock
+		return nil
+	}))
+
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+	assert.Equal(t, []int{1, 2}, counts)
+}
+
+func TestSinkConcurrentLaps(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.AddSink(NewNDJSONSink(&buf))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sw.Lap("lap")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, strings.Count(buf.String(), "\n"))
+}
+
+func TestNDJSONFormatting(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeNDJSON)
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+
+	lines := strings.Split(strings.TrimSuffix(sw.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"state":"lap1"`)
+	assert.Contains(t, lines[1], `"state":"lap2"`)
+}
+
+func TestClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	sink := NewNDJSONSink(w)
+
+	sw := New(0, true)
+	sw.AddSink(sink)
+	sw.Lap("lap1")
+	assert.Empty(t, buf.String(), "buffered")
+
+	assert.NoError(t, sw.Close(context.Background()))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.False(t, sw.active())
+
+	sw.Lap("lap2")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"), "sinks are detached")
+	assert.Equal(t, ErrClosed, sink.WriteLap(Lap{state: "lap3"}))
+}
+
+func TestCloseReturnsFirstError(t *testing.T) {
+	sw := New(0, true)
+	sw.AddSink(SinkFunc(func(lap Lap) error { return nil }))
+	sw.AddSink(closerFunc(func(ctx context.Context) error { return errors.New("failed") }))
+
+	assert.EqualError(t, sw.Close(context.Background()), "failed")
+}
+
+type closerFunc func(ctx context.Context) error
+
+func (f closerFunc) WriteLap(lap Lap) error          { return nil }
+func (f closerFunc) Close(ctx context.Context) error { return f(ctx) }
+
+func TestOnLap(t *testing.T) {
+	sw := New(0, true)
+	var first, second []string
+	sw.OnLap(func(lap Lap) {
+		first = append(first, lap.State())
+		assert.Len(t, sw.Laps(), len(first), "called outside of the lock")
+	})
+	sw.OnLap(func(lap Lap) { second = append(second, lap.State()) })
+
+	sw.Lap("db")
+	sw.Lap("render")
+	assert.Equal(t, []string{"db", "render"}, first)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,64 @@
+package stopwatch
+
+import (
+	"time"
+)
+
+// SLAReport classifies a run and its laps against a target duration
+type SLAReport struct {
+	TargetMs  float64      `json:"target_ms"`
+	ElapsedMs float64      `json:"elapsed_ms"`
+	Within    bool         `json:"within"`
+	Laps      []SLALapInfo `json:"laps"`
+}
+
+// SLALapInfo shows how much of the target a lap took
+type SLALapInfo struct {
+	State string  `json:"state"`
+	Ms    float64 `json:"ms"`
+	// Share is the percentage of the target taken by the lap
+	Share float64 `json:"share"`
+	// Within is false if the lap alone exceeded the target
+	Within bool `json:"within"`
+}
+
+// WithinSLA reports whether the elapsed time is within the target
+func (s *Stopwatch) WithinSLA(target time.Duration) bool {
+	s.rlock()
+	defer s.runlock()
+	return s.ElapsedTime() <= target
+}
+
+// SLAReport classifies the run and every lap against the target
+func (s *Stopwatch) SLAReport(target time.Duration) SLAReport {
+	s.rlock()
+	defer s.runlock()
+
+	elapsed := s.ElapsedTime()
+	report := SLAReport{
+		TargetMs:  milliseconds(target),
+		ElapsedMs: milliseconds(elapsed),
+		Within:    elapsed <= target,
+		Laps:      make([]SLALapInfo, len(s.laps)),
+	}
+	for i, lap := range s.laps {
+		info := SLALapInfo{
+			State:  lap.state,
+			Ms:     milliseconds(lap.duration),
+			Within: lap.duration <= target,
+		}
+		if target > 0 {
+			info.Share = float64(lap.duration) / float64(target) * 100
+		}
+		report.Laps[i] = info
+	}
+	return report
+}
+
+// SetSLA sets the target of the whole run, FormattingModeJsonFull then includes
+// the target and the verdict. Zero removes the target.
+func (s *Stopwatch) SetSLA(target time.Duration) {
+	s.lock()
+	defer s.unlock()
+	s.sla = target
+}
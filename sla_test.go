@@ -0,0 +1,55 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStoppedStopwatch(laps ...Lap) *Stopwatch {
+	sw := New(0, false)
+	var total time.Duration
+	for i := range laps {
+		laps[i].formatter = defaultFormatter
+		laps[i].offset = total
+		total += laps[i].duration
+	}
+	sw.laps = laps
+	sw.mark = total
+	sw.stop = sw.start.Add(total)
+	return sw
+}
+
+func TestSLAReport(t *testing.T) {
+	sw := newStoppedStopwatch(
+		Lap{state: "db", duration: 30 * time.Millisecond},
+		Lap{state: "render", duration: 120 * time.Millisecond},
+	)
+
+	assert.True(t, sw.WithinSLA(200*time.Millisecond))
+	assert.False(t, sw.WithinSLA(100*time.Millisecond))
+
+	assert.Equal(t, SLAReport{
+		TargetMs:  100,
+		ElapsedMs: 150,
+		Within:    false,
+		Laps: []SLALapInfo{
+			{State: "db", Ms: 30, Share: 30, Within: true},
+			{State: "render", Ms: 120, Share: 120, Within: false},
+		},
+	}, sw.SLAReport(100*time.Millisecond))
+}
+
+func TestSLAInFullFormatting(t *testing.T) {
+	sw := newStoppedStopwatch(Lap{state: "db", duration: 30 * time.Millisecond})
+	sw.SetFormattingMode(FormattingModeJsonFull)
+	assert.NotContains(t, sw.String(), "within_sla")
+
+	sw.SetSLA(20 * time.Millisecond)
+	var full FullStopwatch
+	assert.NoError(t, json.Unmarshal([]byte(sw.String()), &full))
+	assert.Equal(t, 20.0, full.SLAMs)
+	assert.False(t, *full.WithinSLA)
+}
@@ -0,0 +1,94 @@
+//go:build go1.21
+// +build go1.21
+
+package stopwatch
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Attribute keys added by SlogHandler
+const (
+	SlogElapsedKey    = "elapsed"
+	SlogCurrentLapKey = "current_lap"
+)
+
+// SlogHandler wraps the handler, adding the elapsed time and the time of the current lap
+// to every record logged while the stopwatch runs, so logs of a job are anchored in its timing:
+//
+//	logger := slog.New(sw.SlogHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//
+// Like other record attributes, they land in the current group of the logger.
+func (s *Stopwatch) SlogHandler(h slog.Handler) slog.Handler {
+	return &slogHandler{sw: s, next: h}
+}
+
+type slogHandler struct {
+	sw   *Stopwatch
+	next slog.Handler
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.sw.rlock()
+	running := h.sw.active()
+	elapsed := h.sw.ElapsedTime()
+	mark := h.sw.mark
+	h.sw.runlock()
+
+	if running {
+		r = r.Clone()
+		r.AddAttrs(slog.Duration(SlogElapsedKey, elapsed), slog.Duration(SlogCurrentLapKey, elapsed-mark))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{sw: h.sw, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{sw: h.sw, next: h.next.WithGroup(name)}
+}
+
+// SlogAttrs returns an attribute per field of the JSON object modes, with the same keys,
+// see SetKeyNaming, SetDuplicateKeys and SetFlattenedData, and the correlation ID if set.
+// Laps are durations, DuplicateKeysArray makes them slices of durations.
+func (s *Stopwatch) SlogAttrs() []slog.Attr {
+	s.rlock()
+	defer s.runlock()
+
+	fields := s.objectFields(func(Lap) string { return "" })
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	if s.correlationID != "" {
+		attrs = append(attrs, slog.String(CorrelationIDKey, s.correlationID))
+	}
+	for _, field := range fields {
+		switch raw := field.raw.(type) {
+		case Lap:
+			attrs = append(attrs, slog.Duration(field.key, raw.duration))
+		case []Lap:
+			durations := make([]time.Duration, len(raw))
+			for i, lap := range raw {
+				durations[i] = lap.duration
+			}
+			attrs = append(attrs, slog.Any(field.key, durations))
+		default:
+			attrs = append(attrs, slog.Any(field.key, raw))
+		}
+	}
+	return attrs
+}
+
+// LogValue makes the stopwatch a group of SlogAttrs when logged with slog,
+// so laps land as structured fields rather than a JSON string escaped by the handler:
+//
+//	logger.Info("request done", "timings", sw)
+func (s *Stopwatch) LogValue() slog.Value {
+	return slog.GroupValue(s.SlogAttrs()...)
+}
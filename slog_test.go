@@ -0,0 +1,93 @@
+//go:build go1.21
+// +build go1.21
+
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, true)
+	sw.start = sw.start.Add(-time.Second)
+	sw.mark = 900 * time.Millisecond
+	logger := slog.New(sw.SlogHandler(slog.NewJSONHandler(&buf, nil))).With("job", "import")
+
+	logger.Info("loaded")
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "import", record["job"])
+	assert.True(t, record[SlogElapsedKey].(float64) >= float64(time.Second))
+	assert.True(t, record[SlogCurrentLapKey].(float64) >= float64(100*time.Millisecond))
+	assert.True(t, record[SlogCurrentLapKey].(float64) < record[SlogElapsedKey].(float64))
+
+	buf.Reset()
+	sw.Stop()
+	logger.Info("done")
+	record = nil
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, SlogElapsedKey)
+}
+
+func TestSlogLogValuer(t *testing.T) {
+	var buf bytes.Buffer
+	sw := New(0, false)
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{{state: "db", duration: time.Millisecond}, {state: "render", duration: 2 * time.Millisecond}}
+
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("done", "timings", sw)
+	var record struct {
+		Timings map[string]interface{} `json:"timings"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, map[string]interface{}{
+		CorrelationIDKey: "req-1",
+		"db":             float64(time.Millisecond),
+		"render":         float64(2 * time.Millisecond),
+	}, record.Timings)
+}
+
+func TestSlogAttrsDuplicateKeys(t *testing.T) {
+	sw := New(0, false)
+	sw.SetFlattenedData("rows")
+	sw.laps = []Lap{
+		{state: "db", duration: time.Millisecond, data: map[string]interface{}{"rows": 2}},
+		{state: "render", duration: 2 * time.Millisecond},
+		{state: "db", duration: 3 * time.Millisecond},
+	}
+
+	assert.Equal(t, []slog.Attr{
+		slog.Duration("db", time.Millisecond),
+		slog.Any("db_rows", 2),
+		slog.Duration("render", 2*time.Millisecond),
+		slog.Duration("db", 3*time.Millisecond),
+	}, sw.SlogAttrs())
+
+	sw.SetDuplicateKeys(DuplicateKeysIndex)
+	assert.Equal(t, []slog.Attr{
+		slog.Duration("db", time.Millisecond),
+		slog.Any("db_rows", 2),
+		slog.Duration("render", 2*time.Millisecond),
+		slog.Duration("db_2", 3*time.Millisecond),
+	}, sw.SlogAttrs())
+
+	sw.SetDuplicateKeys(DuplicateKeysSum)
+	assert.Equal(t, []slog.Attr{
+		slog.Duration("db", 4*time.Millisecond),
+		slog.Duration("render", 2*time.Millisecond),
+	}, sw.SlogAttrs())
+
+	sw.SetDuplicateKeys(DuplicateKeysArray)
+	assert.Equal(t, []slog.Attr{
+		slog.Any("db", []time.Duration{time.Millisecond, 3 * time.Millisecond}),
+		slog.Any("db_rows", []interface{}{2, nil}),
+		slog.Any("render", []time.Duration{2 * time.Millisecond}),
+	}, sw.SlogAttrs())
+}
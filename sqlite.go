@@ -0,0 +1,94 @@
+package stopwatch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLiteConfig configures SQLiteSink
+type SQLiteConfig struct {
+	// DB is a database opened with any SQLite driver, e.g. sql.Open("sqlite3", "timings.db"),
+	// so the stopwatch doesn't depend on one
+	DB *sql.DB
+	// Table receives laps, "laps" if empty. It is created if it doesn't exist.
+	// The name is quoted, so it may be any string.
+	Table string
+	// RunID tells runs apart in the table, the run ID of laps if empty, see Stopwatch.RunID
+	RunID string
+}
+
+// SQLiteSink writes laps into a local SQLite table, one row per lap:
+// run_id, state, started_at, duration_ms, data as JSON and correlation_id.
+// It allows ad-hoc SQL over many runs, e.g.
+//
+//	SELECT state, avg(duration_ms) FROM laps GROUP BY state
+type SQLiteSink struct {
+	cfg    SQLiteConfig
+	insert *sql.Stmt
+}
+
+// NewSQLiteSink creates the table if needed and prepares the insert statement
+func NewSQLiteSink(ctx context.Context, cfg SQLiteConfig) (*SQLiteSink, error) {
+	if cfg.Table == "" {
+		cfg.Table = "laps"
+	}
+
+	table := sqlIdentifier(cfg.Table)
+	_, err := cfg.DB.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	run_id TEXT NOT NULL,
+	state TEXT NOT NULL,
+	started_at TEXT NOT NULL,
+	duration_ms REAL NOT NULL,
+	data TEXT,
+	correlation_id TEXT
+)`, table))
+	if err != nil {
+		return nil, err
+	}
+
+	insert, err := cfg.DB.PrepareContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (run_id, state, started_at, duration_ms, data, correlation_id) VALUES (?, ?, ?, ?, ?, ?)`, table))
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteSink{cfg: cfg, insert: insert}, nil
+}
+
+// sqlIdentifier quotes a name for SQL, double quotes inside are doubled
+func sqlIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// WriteLap inserts a row for the lap
+func (q *SQLiteSink) WriteLap(lap Lap) error {
+	var data interface{} // NULL without data
+	if len(lap.data) > 0 {
+		encoded, err := json.Marshal(lap.data)
+		if err != nil {
+			return err
+		}
+		data = string(encoded)
+	}
+	var correlationID interface{}
+	if lap.correlationID != "" {
+		correlationID = lap.correlationID
+	}
+
+	runID := q.cfg.RunID
+	if runID == "" {
+		runID = lap.runID
+	}
+
+	startedAt := lap.end.Add(-lap.duration).UTC().Format(time.RFC3339Nano)
+	_, err := q.insert.Exec(runID, lap.state, startedAt, milliseconds(lap.duration), data, correlationID)
+	return err
+}
+
+// Close closes the prepared statement, the database is left open
+func (q *SQLiteSink) Close(ctx context.Context) error {
+	return q.insert.Close()
+}
@@ -0,0 +1,95 @@
+package stopwatch
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingDriver is a database/sql driver recording executed statements,
+// so the sink is tested without an SQLite driver
+type recordingDriver struct {
+	mu    sync.Mutex
+	execs []recordedExec
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+// Connect and Driver make it a driver.Connector for sql.OpenDB, so it isn't registered globally
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) { return recordingConn{d}, nil }
+func (d *recordingDriver) Driver() driver.Driver                        { return d }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{d: c.d, query: query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, recordedExec{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestSQLiteSink(t *testing.T) {
+	recorder := &recordingDriver{}
+	db := sql.OpenDB(recorder)
+	defer db.Close()
+
+	sink, err := NewSQLiteSink(context.Background(), SQLiteConfig{DB: db, RunID: "run-1"})
+	assert.NoError(t, err)
+
+	sw := New(0, true)
+	sw.AddSink(sink)
+	sw.SetCorrelationID("req-1")
+	sw.Lap("lap1")
+	sw.LapWithData("lap2", map[string]interface{}{"rows": 2})
+	assert.NoError(t, sw.Close(context.Background()))
+
+	assert.Len(t, recorder.execs, 3)
+	assert.True(t, strings.HasPrefix(recorder.execs[0].query, `CREATE TABLE IF NOT EXISTS "laps"`))
+
+	assert.True(t, strings.HasPrefix(recorder.execs[1].query, `INSERT INTO "laps"`))
+	args := recorder.execs[1].args
+	assert.Equal(t, "run-1", args[0])
+	assert.Equal(t, "lap1", args[1])
+	assert.IsType(t, float64(0), args[3])
+	assert.Nil(t, args[4], "no data")
+	assert.Equal(t, "req-1", args[5])
+
+	assert.Equal(t, `{"rows":2}`, recorder.execs[2].args[4])
+}
+
+func TestSQLiteSinkTable(t *testing.T) {
+	recorder := &recordingDriver{}
+	db := sql.OpenDB(recorder)
+	defer db.Close()
+
+	_, err := NewSQLiteSink(context.Background(), SQLiteConfig{DB: db, Table: `my "laps"\x`})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(recorder.execs[0].query, `CREATE TABLE IF NOT EXISTS "my ""laps""\x" (`))
+}
@@ -0,0 +1,101 @@
+package stopwatch
+
+import "sort"
+
+// AggregatedLapsKey is the lap data key holding the number of laps merged into one by SetStateCap
+const AggregatedLapsKey = "aggregated_laps"
+
+// SetStateCap limits stored laps of every state: the first k and the last k laps are kept,
+// laps in between are merged into a single lap with the total duration and the number
+// of merged laps in AggregatedLapsKey data. Lap data of merged laps is lost.
+// Laps stored already, or loaded later by LoadFrom or UnmarshalJSON, are capped too.
+// It keeps a pathological loop from evicting laps of other states. Zero turns the cap off.
+func (s *Stopwatch) SetStateCap(k int) {
+	s.lock()
+	defer s.unlock()
+	s.stateCap = k
+	s.recapLocked()
+}
+
+// recapLocked counts stored laps of every state again, merging laps over the cap,
+// e.g. after laps are loaded. It must be called under the lock.
+func (s *Stopwatch) recapLocked() {
+	s.stored = nil
+	s.capped = nil
+	if s.stateCap <= 0 {
+		return
+	}
+	s.stored = make(map[string]int)
+	s.capped = make(map[string][]uint64)
+	laps := s.laps
+	s.laps = make([]Lap, 0, len(laps))
+	for _, lap := range laps {
+		if lap.seq == 0 {
+			// unmarshaled from a mode without sequence numbers, capped laps are found by them
+			lap.seq = s.nextSeq()
+		}
+		s.laps = append(s.laps, lap)
+		s.capState(lap)
+	}
+}
+
+// capState is called under the lock after the lap was appended. Laps of a state after
+// its first k laps are tracked by sequence numbers, so the aggregate and the lap merged
+// into it are found without scanning all laps.
+func (s *Stopwatch) capState(lap Lap) {
+	if s.stateCap <= 0 {
+		return
+	}
+	s.stored[lap.state]++
+	if s.stored[lap.state] <= s.stateCap {
+		return
+	}
+	seqs := append(s.capped[lap.state], lap.seq)
+	if len(seqs) > s.stateCap+1 {
+		// the lap after the first k laps holds the aggregate, the next one is merged into it
+		aggregate, merged := s.lapIndex(seqs[0], lap.state), s.lapIndex(seqs[1], lap.state)
+		s.laps[aggregate] = mergeLaps(s.laps[aggregate], s.laps[merged])
+		s.laps = append(s.laps[:merged], s.laps[merged+1:]...)
+		s.stored[lap.state]--
+		seqs = append(seqs[:1], seqs[2:]...)
+	}
+	s.capped[lap.state] = seqs
+}
+
+// uncapLap is called under the lock when a stored lap is evicted. Evicted laps are
+// the oldest, so the first lap after the first k laps of the state takes its place.
+func (s *Stopwatch) uncapLap(lap Lap) {
+	s.stored[lap.state]--
+	if seqs := s.capped[lap.state]; len(seqs) > 0 {
+		s.capped[lap.state] = seqs[1:]
+	}
+}
+
+// lapIndex returns the index of the stored lap with the sequence number and the state.
+// Laps are stored in order of sequence numbers, except laps loaded from files.
+func (s *Stopwatch) lapIndex(seq uint64, state string) int {
+	i := sort.Search(len(s.laps), func(i int) bool { return s.laps[i].seq >= seq })
+	if i < len(s.laps) && s.laps[i].seq == seq && s.laps[i].state == state {
+		return i
+	}
+	for i, lap := range s.laps {
+		if lap.seq == seq && lap.state == state {
+			return i
+		}
+	}
+	return -1
+}
+
+func mergeLaps(aggregate, lap Lap) Lap {
+	count := 1
+	switch n := aggregate.data[AggregatedLapsKey].(type) {
+	case int:
+		count = n
+	case float64: // loaded through JSON
+		count = int(n)
+	}
+	aggregate.duration += lap.duration
+	aggregate.end = lap.end
+	aggregate.data = map[string]interface{}{AggregatedLapsKey: count + 1}
+	return aggregate
+}
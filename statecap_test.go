@@ -0,0 +1,121 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateCap(t *testing.T) {
+	sw := New(0, true)
+	sw.SetStateCap(2)
+
+	sw.Lap("start")
+	for i := 0; i < 10; i++ {
+		sw.LapWithData("loop", map[string]interface{}{"i": i})
+	}
+	sw.Lap("end")
+
+	laps := sw.Laps()
+	var states []string
+	for _, lap := range laps {
+		states = append(states, lap.state)
+	}
+	assert.Equal(t, []string{"start", "loop", "loop", "loop", "loop", "loop", "end"}, states)
+
+	assert.Equal(t, 0, laps[1].data["i"])
+	assert.Equal(t, 1, laps[2].data["i"])
+	assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 6}, laps[3].data)
+	assert.Equal(t, 8, laps[4].data["i"])
+	assert.Equal(t, 9, laps[5].data["i"])
+
+	var total time.Duration
+	for _, lap := range laps {
+		total += lap.duration
+	}
+	assert.Equal(t, sw.mark, total, "laps still add up to the total")
+}
+
+func TestStateCapWithMaxLaps(t *testing.T) {
+	sw := New(0, true)
+	sw.SetStateCap(1)
+	sw.SetMaxLaps(4)
+
+	for i := 0; i < 5; i++ {
+		sw.Lap("loop")
+	}
+	sw.Lap("other")
+	sw.Lap("other2")
+	sw.Lap("loop")
+
+	assert.Len(t, sw.Laps(), 4)
+	assert.Equal(t, 2, sw.stored["loop"])
+}
+
+func TestStateCapInterleaved(t *testing.T) {
+	sw := New(0, true)
+	sw.SetStateCap(1)
+	for i := 0; i < 10; i++ {
+		sw.LapWithData("a", map[string]interface{}{"i": i})
+		sw.LapWithData("b", map[string]interface{}{"i": i})
+	}
+
+	laps := sw.Laps()
+	var states []string
+	for _, lap := range laps {
+		states = append(states, lap.state)
+	}
+	assert.Equal(t, []string{"a", "b", "a", "b", "a", "b"}, states)
+	assert.Equal(t, 0, laps[1].data["i"])
+	assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 8}, laps[2].data)
+	assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 8}, laps[3].data)
+	assert.Equal(t, 9, laps[5].data["i"])
+	assert.Len(t, sw.capped["a"], 2, "the aggregate and the last lap")
+}
+
+func TestStateCapLoaded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stopwatch.json")
+
+	saved := New(0, true)
+	for i := 0; i < 6; i++ {
+		saved.Lap("a")
+	}
+	assert.NoError(t, saved.SaveTo(path))
+
+	sw := New(0, true)
+	sw.SetStateCap(1)
+	assert.NoError(t, sw.LoadFrom(path))
+	assert.Len(t, sw.Laps(), 3, "the first, the aggregate and the last")
+	for i := 0; i < 3; i++ {
+		sw.Lap("a")
+	}
+	laps := sw.Laps()
+	if assert.Len(t, laps, 3) {
+		assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 7}, laps[1].data)
+	}
+
+	// aggregates come back from JSON as float64
+	assert.NoError(t, sw.SaveTo(path))
+	assert.NoError(t, sw.LoadFrom(path))
+	sw.Lap("a")
+	assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 8}, sw.Laps()[1].data)
+
+	unmarshaled := New(0, true)
+	unmarshaled.SetStateCap(1)
+	assert.NoError(t, json.Unmarshal([]byte(`[{"state":"a","time":"1ms"},{"state":"a","time":"2ms"},{"state":"a","time":"3ms"},{"state":"a","time":"4ms"}]`), unmarshaled))
+	unmarshaled.Lap("a")
+	laps = unmarshaled.Laps()
+	if assert.Len(t, laps, 3) {
+		assert.Equal(t, time.Millisecond, laps[0].duration)
+		assert.Equal(t, map[string]interface{}{AggregatedLapsKey: 3}, laps[1].data)
+		assert.Equal(t, 9*time.Millisecond, laps[1].duration)
+	}
+}
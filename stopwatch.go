@@ -1,9 +1,11 @@
 package stopwatch
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 )
 
@@ -15,10 +17,63 @@ func defaultFormatter(duration time.Duration) string {
 // to give you some insight into how long things take for your app
 type Stopwatch struct {
 	start, stop    time.Time     // no need for lap, see mark
+	clock          Clock         // nil reads the system time
 	mark           time.Duration // mark is the duration from the start that the most recent lap was started
-	laps           []Lap         //
+	paused         time.Duration // total time the stopwatch was stopped before it was started again
+	adjusted       time.Duration // total of AddElapsed and SubtractElapsed
+	adjustments    []Adjustment
+	activeSince    time.Time
+	intervals      []Interval
+	pauses         []Pause
+	laps           []Lap //
 	formatter      func(time.Duration) string
 	formattingMode FormattingMode
+	keyNaming      func(string) string
+	duplicateKeys  DuplicateKeys
+	flattenedData  []string
+	lastByState    map[string]uint64
+	seq            uint64 // of the last lap
+	runID          string
+	schema         bool
+	maxState       int
+	maxValue       int
+	sanitizer      Sanitizer
+	redacted       map[string]struct{}
+	precision      int           // decimal places of milliseconds in FormattingModeJsonMsObject
+	disabled       bool          // disabled stopwatch does not record laps
+	maxLaps        int           // only the most recent laps are kept, 0 means unlimited
+	integerUnit    time.Duration // unit of FormattingModeJsonIntObject
+	sinks          []Sink        // receive every recorded lap
+	eventLog       func(Event)   // receives start, stop and lap events
+	wal            *writeAheadLog
+	walErr         error
+	noLocking      bool          // caller guarantees single-goroutine access
+	resolution     time.Duration // laps are rounded to it, 0 means no rounding
+	traceExtractor TraceExtractor
+	attrExtractor  AttributeExtractor
+	attrKeys       []string // allowed by SetContextAttributes
+	correlationID  string
+	gcpProject     string                // Google Cloud project ID for trace fields of FormattingModeGoogleCloud
+	emfNamespace   string                // CloudWatch namespace of FormattingModeCloudWatchEMF
+	skipRate       float64               // fraction of laps not recorded, 0 means none, see SetSampling
+	counts         map[string]stateStats // every lap is counted, even not sampled or evicted
+	rateLimit      int                   // max laps per state per second, 0 means unlimited
+	rateWindows    map[string]*rateWindow
+	dropped        int            // laps dropped by the rate limit
+	stateCap       int            // first and last laps kept per state, 0 means unlimited
+	stored         map[string]int // number of stored laps per state, maintained with capped if stateCap is set
+	capped         map[string][]uint64
+	allowedStates  map[string]struct{}
+	sla            time.Duration // target of the whole run included into FormattingModeJsonFull
+	budgetWatch    *budgetWatch
+	watchdog       *watchdog
+	children       []child
+	severityBands  map[string]SeverityBands
+	template       *template.Template // of FormattingModeTemplate
+	slowLap        time.Duration      // laps taking longer get a goroutine snapshot
+	slowLapDump    bool
+	lockProfile    bool       // laps get block and mutex profile deltas
+	lockTotals     lockTotals // at the last lap
 	sync.RWMutex
 }
 
@@ -33,83 +88,255 @@ const (
 	// FormattingModeJsonMsObject formats Stopwatch to the form object with property-per-lap, where values are numbers {"Lap1":10.1, "Lap2":20.2}
 	// It's compatitable with ELK. Does not support additional lap data
 	FormattingModeJsonMsObject FormattingMode = "JSON_OBJECT_MS"
+	// FormattingModeJsonIntObject formats Stopwatch to the form object with property-per-lap, where values are
+	// integer numbers of a unit set by SetIntegerUnit, microseconds by default {"Lap1":10123, "Lap2":20234}
+	// It's for aggregation systems that misbehave with floats. Does not support additional lap data
+	FormattingModeJsonIntObject FormattingMode = "JSON_OBJECT_INT"
+	// FormattingModeJsonDetailed formats Stopwatch to the form of an array of lap objects with durations,
+	// offsets from the start and lap data [{"state":"db","ms":12.3,"offset_ms":4.5,"data":{...}}, ...]
+	FormattingModeJsonDetailed FormattingMode = "JSON_DETAILED"
+	// FormattingModeNDJSON formats Stopwatch to lines of lap objects as in FormattingModeJsonDetailed,
+	// one lap per line. See also NewNDJSONSink to write laps as they are recorded
+	FormattingModeNDJSON FormattingMode = "NDJSON"
+	// FormattingModeJsonFull formats Stopwatch to an object with its running state, start and stop times,
+	// total paused time and laps as in FormattingModeJsonDetailed
+	// {"running":false,"started_at":"...","stopped_at":"...","elapsed_ms":20.1,"paused_ms":3.5,"laps":[...]}
+	FormattingModeJsonFull FormattingMode = "JSON_FULL"
+	// FormattingModeECS formats Stopwatch to an array of Elastic Common Schema documents, one per lap,
+	// with event.action, event.duration in nanoseconds, event.start, event.end and lap data in labels
+	FormattingModeECS FormattingMode = "ECS"
+	// FormattingModeGoogleCloud formats Stopwatch to a Google Cloud Logging structured payload with severity,
+	// message, trace fields (see SetGoogleCloudProject) and laps as in FormattingModeJsonDetailed
+	FormattingModeGoogleCloud FormattingMode = "GOOGLE_CLOUD"
+	// FormattingModeCloudWatchEMF formats Stopwatch to an AWS CloudWatch Embedded Metric Format document
+	// with a metric in milliseconds per lap state, named like "lap_db", see SetCloudWatchNamespace
+	// and CloudWatchMetricPrefix. CloudWatch accepts up to 100 metrics in a document
+	FormattingModeCloudWatchEMF FormattingMode = "CLOUDWATCH_EMF"
+	// FormattingModeFlat formats Stopwatch to a single flat object per run for data warehouse tables:
+	// run_id (the run ID, or the correlation ID if the run ID is cleared with SetRunID(""), see RunID),
+	// total_ms, started_at and a "<state>_ms" column per lap state
+	// with the total of its laps {"run_id":"run-1","total_ms":30.2,"started_at":"...","db_query_ms":20.1}.
+	// Lap columns clashing with the fixed ones are prefixed with "lap_", e.g. "lap_total_ms".
+	// Children add their total_ms and lap columns prefixed with "child_<name>_", e.g. "child_db_total_ms"
+	FormattingModeFlat FormattingMode = "JSON_FLAT"
+	// FormattingModeTemplate formats Stopwatch with a text/template set by SetTemplate
+	FormattingModeTemplate FormattingMode = "TEMPLATE"
+	// FormattingModeJsonSummary formats Stopwatch to an array of per-state statistics, see Summary
+	// [{"state":"db","count":500,"total_ms":1200.5,"min_ms":1.1,"max_ms":20.3,"mean_ms":2.4,"median_ms":2.1,...}]
+	FormattingModeJsonSummary FormattingMode = "JSON_SUMMARY"
 
 	defaultFormattingMode FormattingMode = FormattingModeJsonArray
+
+	defaultPrecision   = 3
+	defaultIntegerUnit = time.Microsecond
 )
 
 // New creates a new stopwatch with starting time offset by
 // a user defined value. Negative offsets result in a countdown
 // prior to the start of the stopwatch.
 func New(offset time.Duration, active bool) *Stopwatch {
-	var sw Stopwatch
+	return NewWithClock(offset, active, nil)
+}
+
+// NewWithClock creates a new stopwatch like New, reading time from the clock,
+// so tests can assert exact lap durations. Nil clock reads the system time.
+func NewWithClock(offset time.Duration, active bool, clock Clock) *Stopwatch {
+	sw := Stopwatch{clock: clock}
 	sw.Reset(offset, active)
 	sw.SetFormatter(defaultFormatter)
 	sw.SetFormattingMode(defaultFormattingMode)
+	sw.SetPrecision(defaultPrecision)
+	sw.SetIntegerUnit(defaultIntegerUnit)
+	envDefaults().apply(&sw)
 	return &sw
 }
 
 // SetFormatter takes a function that converts time.Duration into a string
 func (s *Stopwatch) SetFormatter(formatter func(time.Duration) string) {
-	s.Lock()
+	s.lock()
 	s.formatter = formatter
-	s.Unlock()
+	s.unlock()
 }
 
-// MarshalJSON converts into a slice of bytes
+// MarshalJSON converts into a slice of bytes holding a single JSON value.
+// FormattingModeNDJSON writes several values and FormattingModeTemplate may not write JSON
+// at all, so they fall back to FormattingModeJsonFull.
 func (s *Stopwatch) MarshalJSON() ([]byte, error) {
-	return []byte(s.String()), nil
+	defer countFormatting(time.Now())
+	s.rlock()
+	mode := s.formattingMode
+	s.runlock()
+	if mode == FormattingModeNDJSON || mode == FormattingModeTemplate {
+		mode = FormattingModeJsonFull
+	}
+
+	result, err := s.formatAs(mode)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(result), nil
 }
 
 func (s *Stopwatch) String() string {
+	result, err := s.format()
+	if err != nil {
+		return `{"error":` + jsonString(err.Error()) + "}"
+	}
+	return result
+}
+
+// format renders the stopwatch according to the formatting mode
+func (s *Stopwatch) format() (string, error) {
+	defer countFormatting(time.Now())
+	s.rlock()
+	mode := s.formattingMode
+	s.runlock()
+	return s.formatAs(mode)
+}
+
+// formatAs renders the stopwatch in the given formatting mode
+func (s *Stopwatch) formatAs(mode FormattingMode) (string, error) {
 
-	s.RLock()
-	defer s.RUnlock()
+	s.rlock()
+	defer s.runlock()
+
+	mode = defaultedFormattingMode(mode)
+	output, err := s.formatLocked(mode)
+	if err != nil || len(s.children) == 0 {
+		return output, err
+	}
+	return s.addChildren(output, mode)
+}
 
-	switch defaultedFormattingMode(s.formattingMode) {
+// formatLocked must be called under the read lock
+func (s *Stopwatch) formatLocked(mode FormattingMode) (string, error) {
+	switch mode {
 	case FormattingModeJsonSimpleObject:
 		return s.formatAsObject(func(lap Lap) string {
-			return fmt.Sprintf(`"%s":"%s"`, lap.state, lap.formatter(lap.duration))
-		})
+			return jsonString(lap.formattedDuration())
+		}), nil
 
 	case FormattingModeJsonMsObject:
 		return s.formatAsObject(func(lap Lap) string {
-			return fmt.Sprintf(`"%s":%.3f`, lap.state, float64(lap.duration.Microseconds())/1000.0) // ms 1234.567
-		})
+			return fmt.Sprintf(`%.*f`, s.precision, float64(lap.duration.Microseconds())/1000.0) // ms 1234.567
+		}), nil
+
+	case FormattingModeJsonIntObject:
+		return s.formatAsObject(func(lap Lap) string {
+			return fmt.Sprintf(`%d`, lap.duration.Round(s.integerUnit)/s.integerUnit)
+		}), nil
+
+	case FormattingModeJsonDetailed:
+		return s.formatDetailed(s.treeLaps())
+
+	case FormattingModeNDJSON:
+		return s.formatNDJSON(s.treeLaps())
+
+	case FormattingModeJsonFull:
+		return s.formatFull()
+
+	case FormattingModeECS:
+		return s.formatECS(s.treeLaps())
+
+	case FormattingModeGoogleCloud:
+		return s.formatGoogleCloud()
+
+	case FormattingModeCloudWatchEMF:
+		return s.formatCloudWatchEMF()
+
+	case FormattingModeFlat:
+		return s.formatFlat()
+
+	case FormattingModeTemplate:
+		return s.formatTemplate()
+
+	case FormattingModeJsonSummary:
+		return s.formatSummary()
 
 	case FormattingModeJsonArray:
 		fallthrough
 	default:
-		results := make([]string, len(s.laps))
-		for i, v := range s.laps {
+		laps := s.treeLaps()
+		results := make([]string, len(laps))
+		for i, v := range laps {
 			results[i] = v.String()
 		}
-		return fmt.Sprintf("[%s]", strings.Join(results, ", "))
+		return fmt.Sprintf("[%s]", strings.Join(results, ", ")), nil
 	}
 
 }
 
 func (s *Stopwatch) formatAsObject(lapValueFormatter func(Lap) string) string {
-	results := make([]string, len(s.laps))
-	for i, lap := range s.laps {
-		results[i] = lapValueFormatter(lap)
+	results := make([]string, 0, len(s.laps)+1)
+	if s.correlationID != "" {
+		results = append(results, jsonString(CorrelationIDKey)+":"+jsonString(s.correlationID))
+	}
+	for _, field := range s.objectFields(lapValueFormatter) {
+		results = append(results, jsonString(field.key)+":"+field.value)
 	}
 	return fmt.Sprintf("{%s}", strings.Join(results, ", "))
 }
 
+// jsonString quotes and escapes the string for JSON like encoding/json,
+// leaving HTML characters as they are
+func jsonString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // strings always encode
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 // Reset allows the re-use of a Stopwatch instead of creating
 // a new one.
 func (s *Stopwatch) Reset(offset time.Duration, active bool) {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-	s.start = now.Add(-offset)
+	now := s.now()
+	var log func(Event)
+	defer func() {
+		// called after unlock
+		for _, event := range resetEvents(now.Add(-offset), active) {
+			if log != nil {
+				log(event)
+			}
+		}
+	}()
+	s.lock()
+	defer s.unlock()
+	if !s.walWrite(resetEvents(now.Add(-offset), active)...) {
+		return
+	}
+	log = s.eventLog
+	stop := now
 	if active {
-		s.stop = time.Time{}
-	} else {
-		s.stop = now
+		stop = time.Time{}
 	}
+	s.resetLocked(now.Add(-offset), stop)
+}
+
+// resetLocked clears the timing state and laps, the stopwatch is running if stop is zero.
+// It must be called under the lock.
+func (s *Stopwatch) resetLocked(start, stop time.Time) {
+	s.start = start
+	s.stop = stop
 	s.mark = 0
+	s.seq = 0
+	s.runID = NewRunID()
+	s.paused = 0
+	s.adjusted = 0
+	s.adjustments = nil
+	s.children = nil
+	s.activeSince = s.start
+	s.intervals = nil
+	s.pauses = nil
 	s.laps = nil
+	s.lastByState = nil
+	s.counts = nil
+	s.rateWindows = nil
+	s.dropped = 0
+	if s.stored != nil {
+		s.stored = make(map[string]int)
+		s.capped = make(map[string][]uint64)
+	}
 }
 
 // Active returns true if the stopwatch is active (counting up)
@@ -119,32 +346,66 @@ func (s *Stopwatch) active() bool {
 
 // Stop makes the stopwatch stop counting up
 func (s *Stopwatch) Stop() {
-	s.Lock()
-	defer s.Unlock()
-	if s.active() {
-		s.stop = time.Now()
+	s.stopAt(s.now(), "")
+}
+
+func (s *Stopwatch) stopAt(now time.Time, reason string) {
+	s.lock()
+	stopped := s.active() && s.walWrite(Event{Kind: EventStop, Time: now, Reason: reason})
+	if stopped {
+		s.stop = now
+		s.intervals = append(s.intervals, Interval{Start: s.activeSince, End: now})
+		s.pauses = append(s.pauses, Pause{Reason: reason, Start: now})
+	}
+	log := s.eventLog
+	s.unlock()
+
+	if stopped && log != nil {
+		log(Event{Kind: EventStop, Time: now, Reason: reason})
 	}
 }
 
 // Start intiates, or resumes the counting up process
 func (s *Stopwatch) Start() {
-	s.Lock()
-	defer s.Unlock()
-	if !s.active() {
-		diff := time.Since(s.stop)
+	s.startAt(s.now())
+}
+
+func (s *Stopwatch) startAt(now time.Time) {
+	s.lock()
+	started := !s.active() && s.walWrite(Event{Kind: EventStart, Time: now})
+	if started {
+		diff := now.Sub(s.stop)
 		s.start = s.start.Add(diff)
+		s.paused += diff
 		s.stop = time.Time{}
+		s.activeSince = now
+		if n := len(s.pauses); n > 0 {
+			s.pauses[n-1].End = now
+		}
+	}
+	log := s.eventLog
+	s.unlock()
+
+	if started && log != nil {
+		log(Event{Kind: EventStart, Time: now})
 	}
 }
 
 // ElapsedTime is the time the stopwatch has been active
 func (s *Stopwatch) ElapsedTime() time.Duration {
 	if s.active() {
-		return time.Since(s.start)
+		return s.now().Sub(s.start)
 	}
 	return s.stop.Sub(s.start)
 }
 
+// elapsedSnapshot is ElapsedTime taken under the read lock, the caller must not hold it
+func (s *Stopwatch) elapsedSnapshot() time.Duration {
+	s.rlock()
+	defer s.runlock()
+	return s.ElapsedTime()
+}
+
 // ElapsedTimeFrom is the time the stopwatch has been active till 'now'
 func (s *Stopwatch) ElapsedTimeFrom(now time.Time) time.Duration {
 	if s.active() {
@@ -155,46 +416,144 @@ func (s *Stopwatch) ElapsedTimeFrom(now time.Time) time.Duration {
 
 // LapTime is the time since the start of the lap
 func (s *Stopwatch) LapTime() time.Duration {
-	s.RLock()
-	defer s.RUnlock()
+	s.rlock()
+	defer s.runlock()
 	return s.ElapsedTime() - s.mark
 }
 
 // Lap starts a new lap, and returns the length of
-// the previous one.
-func (s *Stopwatch) Lap(state string) Lap {
-	return s.LapWithData(state, nil)
+// the previous one. Options add data or set the time of the lap:
+//
+//	sw.Lap("db", stopwatch.WithTag("shard", "3"), stopwatch.WithError(err))
+func (s *Stopwatch) Lap(state string, opts ...LapOption) Lap {
+	if len(opts) == 0 {
+		return s.LapWithData(state, nil)
+	}
+	o := lapOptions{now: s.now()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return s.LapWithDataAndTime(o.now, state, o.data)
+}
+
+// Lapf starts a new lap named by fmt.Sprintf(format, args...), and returns the length of
+// the previous one. The name is not formatted at all while the stopwatch is disabled,
+// see SetEnabled, so it's cheap to leave on hot paths.
+func (s *Stopwatch) Lapf(format string, args ...interface{}) Lap {
+	now := s.now()
+
+	s.rlock()
+	disabled, formatter := s.disabled, s.formatter
+	s.runlock()
+	if disabled {
+		return Lap{formatter: formatter}
+	}
+
+	return s.LapWithDataAndTime(now, fmt.Sprintf(format, args...), nil)
 }
 
 // LapWithData starts a new lap, and returns the length of
 // the previous one allowing the user to pass in additional
 // metadata to be recorded.
 func (s *Stopwatch) LapWithData(state string, data map[string]interface{}) Lap {
-	return s.LapWithDataAndTime(time.Now(), state, data)
+	return s.LapWithDataAndTime(s.now(), state, data)
 }
 
 // LapWithDataAndTime starts a new lap from 'now' timestamp, and returns the length of
 // the previous one allowing the user to pass in additional
 // metadata to be recorded.
 func (s *Stopwatch) LapWithDataAndTime(now time.Time, state string, data map[string]interface{}) Lap {
-	s.Lock()
-	defer s.Unlock()
-	elapsed := s.ElapsedTimeFrom(now)
-	lap := Lap{
-		formatter: s.formatter,
-		state:     state,
-		duration:  elapsed - s.mark,
-		data:      data,
+	lap, event, sinks, log := s.recordLap(now, state, data)
+	publishLap(lap, event, sinks, log)
+	return lap
+}
+
+// publishLap notifies the event log and sinks about a recorded lap. It's called outside
+// of the lock, so they may read the stopwatch.
+func publishLap(lap Lap, event Event, sinks []Sink, log func(Event)) {
+	if log != nil {
+		log(event)
+	}
+	for _, sink := range sinks {
+		_ = countExport(sink.WriteLap(lap))
+	}
+}
+
+// recordLap returns the lap, its event, and sinks and the event log to be notified about it
+func (s *Stopwatch) recordLap(now time.Time, state string, data map[string]interface{}) (Lap, Event, []Sink, func(Event)) {
+	s.lock()
+	defer s.unlock()
+	if s.disabled {
+		return Lap{formatter: s.formatter, state: state}, Event{}, nil, nil
+	}
+	// rounding the elapsed time rather than durations keeps laps adding up to the total
+	elapsed := s.ElapsedTimeFrom(now).Round(s.resolution)
+	lap, event, ok := s.processLap(EventLap, state, s.mark, elapsed-s.mark, now, data)
+	if !ok {
+		return lap, event, nil, nil
 	}
 	s.mark = elapsed
+	return lap, event, s.keepLap(lap, now), s.eventLog
+}
+
+// processLap runs lap data through sanitizing, profiling, classification and truncation,
+// and writes the lap event to the write-ahead log. It's shared by laps and group tasks,
+// and must be called under the lock. A lap failing to be logged is not recorded, false is returned.
+func (s *Stopwatch) processLap(kind EventKind, state string, offset, duration time.Duration, end time.Time,
+	data map[string]interface{}) (Lap, Event, bool) {
+	data = s.sanitizeData(data)
+	data = s.flagUnexpectedState(state, data)
+	data = s.profileSlowLap(duration, data)
+	data = s.addLockProfile(data)
+	data = s.classifyLap(state, duration, data)
+	// last, so values added above are truncated too, like goroutine dumps
+	state, data = s.truncateLap(state, data)
+
+	event := Event{Kind: kind, Time: end, State: state, Data: data}
+	if kind == EventTask {
+		event.Delta = duration
+	}
+	if !s.walWrite(event) {
+		return Lap{formatter: s.formatter, state: state}, event, false
+	}
+	return Lap{
+		formatter:     s.formatter,
+		state:         state,
+		offset:        offset,
+		end:           end,
+		duration:      duration,
+		data:          data,
+		correlationID: s.correlationID,
+		seq:           s.nextSeq(),
+		runID:         s.runID,
+	}, event, true
+}
+
+// keepLap counts the lap and keeps it unless it's sampled out or rate limited.
+// It returns sinks to be notified about a kept lap. Must be called under the lock.
+func (s *Stopwatch) keepLap(lap Lap, now time.Time) []Sink {
+	s.countLap(lap)
+	countLapRecorded()
+	if !s.sampled() {
+		countLapsDropped(1)
+		return nil
+	}
+	if !s.rateAllowed(lap.state, now) {
+		s.dropped++
+		countLapsDropped(1)
+		return nil
+	}
 	s.laps = append(s.laps, lap)
-	return lap
+	s.indexLap(lap)
+	s.capState(lap)
+	s.evictLaps()
+	return s.sinks
 }
 
 // Laps returns a slice of completed lap times
 func (s *Stopwatch) Laps() []Lap {
-	s.RLock()
-	defer s.RUnlock()
+	s.rlock()
+	defer s.runlock()
 	laps := make([]Lap, len(s.laps))
 	copy(laps, s.laps)
 	return laps
@@ -202,11 +561,68 @@ func (s *Stopwatch) Laps() []Lap {
 
 // Laps returns a slice of completed lap times
 func (s *Stopwatch) SetFormattingMode(newMode FormattingMode) {
-	s.Lock()
-	defer s.Unlock()
+	s.lock()
+	defer s.unlock()
 	s.formattingMode = newMode
 }
 
+// SetPrecision sets the number of decimal places of milliseconds in FormattingModeJsonMsObject
+func (s *Stopwatch) SetPrecision(precision int) {
+	s.lock()
+	defer s.unlock()
+	s.precision = precision
+}
+
+// SetIntegerUnit sets the unit of lap values in FormattingModeJsonIntObject, e.g. time.Millisecond.
+// Zero or negative units fall back to the default, microseconds.
+func (s *Stopwatch) SetIntegerUnit(unit time.Duration) {
+	if unit <= 0 {
+		unit = defaultIntegerUnit
+	}
+	s.lock()
+	defer s.unlock()
+	s.integerUnit = unit
+}
+
+// SetResolution makes laps recorded from now on rounded to the given resolution, e.g. time.Millisecond.
+// Zero turns rounding off.
+func (s *Stopwatch) SetResolution(resolution time.Duration) {
+	s.lock()
+	defer s.unlock()
+	s.resolution = resolution
+}
+
+// SetEnabled turns lap recording on or off. A disabled stopwatch keeps counting time,
+// but does not record laps.
+func (s *Stopwatch) SetEnabled(enabled bool) {
+	s.lock()
+	defer s.unlock()
+	s.disabled = !enabled
+}
+
+// SetMaxLaps limits the number of stored laps. When the limit is reached,
+// the oldest laps are discarded. Zero means no limit.
+func (s *Stopwatch) SetMaxLaps(maxLaps int) {
+	s.lock()
+	defer s.unlock()
+	s.maxLaps = maxLaps
+	s.evictLaps()
+}
+
+// evictLaps drops the oldest laps over the MaxLaps limit, must be called under the lock
+func (s *Stopwatch) evictLaps() {
+	if s.maxLaps <= 0 || len(s.laps) <= s.maxLaps {
+		return
+	}
+	evicted := s.laps[:len(s.laps)-s.maxLaps]
+	if s.stored != nil {
+		for _, lap := range evicted {
+			s.uncapLap(lap)
+		}
+	}
+	s.laps = s.laps[len(evicted):]
+}
+
 func defaultedFormattingMode(src FormattingMode) FormattingMode {
 
 	if src == "" {
@@ -215,3 +631,54 @@ func defaultedFormattingMode(src FormattingMode) FormattingMode {
 
 	return src
 }
+
+// WithoutLocking turns off all locking for callers who guarantee that the stopwatch is used
+// by a single goroutine, the mutex otherwise dominates the cost of Lap for short sections.
+// Call it right after New, before the stopwatch is shared.
+// Features using the stopwatch from goroutines of their own are off: SetWatchdog does nothing
+// and the total budget of SetBudget is checked by laps only. Don't combine it with anything
+// else reading the stopwatch from another goroutine, like flushers of RegisterFlusher or
+// FlushOnExit formatting it, HoneycombSink.AddWide, LapsHandler or sinks behind AsyncSink
+// reading it.
+func (s *Stopwatch) WithoutLocking() *Stopwatch {
+	s.lock()
+	w, budget := s.watchdog, s.budgetWatch
+	if !s.noLocking {
+		s.noLocking = true
+		s.Unlock() // taken by lock above
+	}
+
+	if w != nil {
+		w.stop()
+	}
+	if budget != nil {
+		budget.mu.Lock()
+		budget.stopTimerLocked()
+		budget.mu.Unlock()
+	}
+	return s
+}
+
+func (s *Stopwatch) lock() {
+	if !s.noLocking {
+		s.Lock()
+	}
+}
+
+func (s *Stopwatch) unlock() {
+	if !s.noLocking {
+		s.Unlock()
+	}
+}
+
+func (s *Stopwatch) rlock() {
+	if !s.noLocking {
+		s.RLock()
+	}
+}
+
+func (s *Stopwatch) runlock() {
+	if !s.noLocking {
+		s.RUnlock()
+	}
+}
@@ -257,3 +257,132 @@ func TestDefaultFormatting(t *testing.T) {
 	assert.Equal(t, "lap2", unmarshalledResult[1]["state"])
 	assert.NotEmpty(t, unmarshalledResult[0]["time"])
 }
+
+func TestMsObjectPrecision(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeJsonMsObject)
+	sw.SetPrecision(0)
+	sw.LapWithDataAndTime(time.Now().Add(1500*time.Millisecond), "lap1", nil)
+
+	assert.Equal(t, `{"lap1":1500}`, sw.String())
+}
+
+func TestDisabled(t *testing.T) {
+	sw := New(0, true)
+	sw.SetEnabled(false)
+	sw.Lap("lap1")
+	assert.Empty(t, sw.Laps())
+
+	sw.SetEnabled(true)
+	sw.Lap("lap2")
+	assert.Len(t, sw.Laps(), 1)
+}
+
+func TestIntObjectFormatting(t *testing.T) {
+	sw := New(0, true)
+	sw.SetFormattingMode(FormattingModeJsonIntObject)
+	sw.laps = []Lap{
+		{state: "lap1", duration: 1500 * time.Microsecond},
+		{state: "lap2", duration: 2499 * time.Nanosecond},
+	}
+	assert.Equal(t, `{"lap1":1500, "lap2":2}`, sw.String())
+
+	sw.SetIntegerUnit(time.Millisecond)
+	assert.Equal(t, `{"lap1":2, "lap2":0}`, sw.String())
+
+	sw.SetIntegerUnit(0)
+	assert.Equal(t, `{"lap1":1500, "lap2":2}`, sw.String(), "falls back to microseconds")
+}
+
+func TestWithoutLocking(t *testing.T) {
+	sw := New(0, true).WithoutLocking()
+	sw.Lap("lap1")
+	sw.Lap("lap2")
+	sw.Stop()
+
+	assert.Len(t, sw.Laps(), 2)
+	assert.NotEmpty(t, sw.String())
+}
+
+func TestWithoutLockingTimers(t *testing.T) {
+	fired := make(chan string, 3)
+	sw := New(0, true)
+	sw.SetWatchdog(time.Millisecond, func(Stall) { fired <- "armed before" })
+	sw.WithoutLocking().WithoutLocking()
+	sw.SetWatchdog(time.Millisecond, func(Stall) { fired <- "watchdog" })
+	sw.SetBudget(Budget{Total: time.Millisecond}, func(Alert) { fired <- "budget" })
+
+	time.Sleep(5 * time.Millisecond)
+	assert.Empty(t, fired, "no timers")
+	sw.Lap("lap")
+	assert.Equal(t, "budget", <-fired, "checked by the lap")
+}
+
+func BenchmarkLap(b *testing.B) {
+	sw := New(0, true)
+	sw.SetMaxLaps(100)
+	for i := 0; i < b.N; i++ {
+		sw.Lap("lap")
+	}
+}
+
+func BenchmarkLapWithoutLocking(b *testing.B) {
+	sw := New(0, true).WithoutLocking()
+	sw.SetMaxLaps(100)
+	for i := 0; i < b.N; i++ {
+		sw.Lap("lap")
+	}
+}
+
+func TestResolution(t *testing.T) {
+	sw := New(0, false)
+	sw.SetResolution(time.Millisecond)
+	now := sw.stop
+
+	sw.LapWithDataAndTime(now, "lap1", nil)
+	sw.stop = now.Add(1400 * time.Microsecond)
+	sw.LapWithDataAndTime(now, "lap2", nil)
+	sw.stop = now.Add(3600 * time.Microsecond)
+	sw.LapWithDataAndTime(now, "lap3", nil)
+
+	laps := sw.Laps()
+	assert.Equal(t, time.Duration(0), laps[0].duration)
+	assert.Equal(t, time.Millisecond, laps[1].duration)
+	assert.Equal(t, 3*time.Millisecond, laps[2].duration)
+	assert.Equal(t, time.Millisecond, laps[2].offset)
+}
+
+type countingStringer struct {
+	calls *int
+}
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "item"
+}
+
+func TestLapf(t *testing.T) {
+	calls := 0
+	sw := New(0, true)
+
+	sw.Lapf("load %s %d", countingStringer{&calls}, 1)
+	assert.Equal(t, "load item 1", sw.Laps()[0].state)
+	assert.Equal(t, 1, calls)
+
+	sw.SetEnabled(false)
+	sw.Lapf("load %s %d", countingStringer{&calls}, 2)
+	assert.Equal(t, 1, calls, "disabled stopwatch must not format the state")
+	assert.Len(t, sw.Laps(), 1)
+}
+
+func TestEscaping(t *testing.T) {
+	sw := New(0, false)
+	sw.SetCorrelationID("a\nb")
+	sw.laps = []Lap{{formatter: defaultFormatter, state: `say "hi"`, duration: time.Millisecond, data: map[string]interface{}{"path": `C:\temp`}}}
+
+	assert.Equal(t, `[{"state":"say \"hi\"", "time":"1ms", "path":"C:\\temp"}]`, sw.String())
+	sw.SetFormattingMode(FormattingModeJsonSimpleObject)
+	assert.Equal(t, `{"correlation_id":"a\nb", "say \"hi\"":"1ms"}`, sw.String())
+	sw.SetFormattingMode(FormattingModeJsonIntObject)
+	assert.Equal(t, `{"correlation_id":"a\nb", "say \"hi\"":1000}`, sw.String())
+}
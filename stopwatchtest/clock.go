@@ -0,0 +1,36 @@
+package stopwatchtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a stopwatch.Clock moved by hand, for deterministic lap durations:
+//
+//	clock := stopwatchtest.NewClock(time.Unix(0, 0))
+//	sw := stopwatch.NewWithClock(0, true, clock)
+//	clock.Advance(10 * time.Millisecond)
+//	lap := sw.Lap("db") // exactly 10ms
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock showing the given time
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,28 @@
+package stopwatchtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	clock := NewClock(time.Unix(0, 0))
+	sw := stopwatch.NewWithClock(0, true, clock)
+
+	clock.Advance(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, sw.Lap("db").Duration())
+
+	clock.Advance(5 * time.Millisecond)
+	sw.Stop()
+	clock.Advance(time.Hour)
+	sw.Start()
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, 16*time.Millisecond, sw.ElapsedTime())
+	assert.Equal(t, 6*time.Millisecond, sw.Lap("render").Duration())
+
+	sw.Reset(time.Second, true)
+	assert.Equal(t, time.Second, sw.ElapsedTime())
+}
@@ -0,0 +1,94 @@
+// Package stopwatchtest provides assertions on stopwatch laps for use in tests
+package stopwatchtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+)
+
+// AssertLapUnder checks that every lap with the given state took less than max.
+// It fails if there is no lap with the given state.
+func AssertLapUnder(t testing.TB, sw *stopwatch.Stopwatch, state string, max time.Duration) bool {
+	t.Helper()
+
+	found := false
+	ok := true
+	for i, lap := range sw.Laps() {
+		if lap.State() != state {
+			continue
+		}
+		found = true
+		if lap.Duration() >= max {
+			t.Errorf("lap %d %q took %s, expected under %s", i, state, lap.Duration(), max)
+			ok = false
+		}
+	}
+	if !found {
+		t.Errorf("no lap with state %q, laps are:\n%s", state, describe(sw.Laps()))
+		return false
+	}
+	return ok
+}
+
+// AssertLapCount checks that the stopwatch has exactly 'expected' laps
+func AssertLapCount(t testing.TB, sw *stopwatch.Stopwatch, expected int) bool {
+	t.Helper()
+
+	laps := sw.Laps()
+	if len(laps) != expected {
+		t.Errorf("expected %d laps, got %d:\n%s", expected, len(laps), describe(laps))
+		return false
+	}
+	return true
+}
+
+// AssertStatesInOrder checks that laps with the given states were recorded in the given order.
+// Other laps may appear between them.
+func AssertStatesInOrder(t testing.TB, sw *stopwatch.Stopwatch, states ...string) bool {
+	t.Helper()
+
+	laps := sw.Laps()
+	next := 0
+	for _, lap := range laps {
+		if next < len(states) && lap.State() == states[next] {
+			next++
+		}
+	}
+	if next < len(states) {
+		t.Errorf("states are not in order, first missing is %q\nexpected: %s\nactual:   %s",
+			states[next], strings.Join(quote(states), ", "), strings.Join(quote(lapStates(laps)), ", "))
+		return false
+	}
+	return true
+}
+
+func lapStates(laps []stopwatch.Lap) []string {
+	states := make([]string, len(laps))
+	for i, lap := range laps {
+		states[i] = lap.State()
+	}
+	return states
+}
+
+func quote(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return quoted
+}
+
+func describe(laps []stopwatch.Lap) string {
+	if len(laps) == 0 {
+		return "\t(no laps)"
+	}
+	lines := make([]string, len(laps))
+	for i, lap := range laps {
+		lines[i] = fmt.Sprintf("\t%d: %q %s", i, lap.State(), lap.Duration())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,61 @@
+package stopwatchtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexus1024/stopwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newStopwatch() *stopwatch.Stopwatch {
+	sw := stopwatch.New(0, true)
+	sw.Lap("parse")
+	sw.Lap("db")
+	sw.Lap("render")
+	return sw
+}
+
+func TestAssertLapUnder(t *testing.T) {
+	sw := newStopwatch()
+
+	assert.True(t, AssertLapUnder(t, sw, "db", time.Minute))
+
+	tb := &recordingTB{}
+	assert.False(t, AssertLapUnder(tb, sw, "db", 0))
+	assert.False(t, AssertLapUnder(tb, sw, "cache", time.Minute))
+	assert.Len(t, tb.errors, 2)
+	assert.Contains(t, tb.errors[1], `"render"`)
+}
+
+func TestAssertLapCount(t *testing.T) {
+	sw := newStopwatch()
+
+	assert.True(t, AssertLapCount(t, sw, 3))
+
+	tb := &recordingTB{}
+	assert.False(t, AssertLapCount(tb, sw, 2))
+	assert.Len(t, tb.errors, 1)
+}
+
+func TestAssertStatesInOrder(t *testing.T) {
+	sw := newStopwatch()
+
+	assert.True(t, AssertStatesInOrder(t, sw, "parse", "render"))
+
+	tb := &recordingTB{}
+	assert.False(t, AssertStatesInOrder(tb, sw, "render", "parse"))
+	assert.Len(t, tb.errors, 1)
+	assert.Contains(t, tb.errors[0], `first missing is "parse"`)
+}
@@ -0,0 +1,166 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Suite runs named functions many times and compares their timings,
+// an ad-hoc benchmark harness for use outside of go test:
+//
+//	suite := stopwatch.NewSuite(100, 10)
+//	suite.Add("json", encodeJSON)
+//	suite.Add("gob", encodeGob)
+//	suite.Run().WriteTable(os.Stdout)
+type Suite struct {
+	runs, warmUp int
+	names        []string
+	funcs        []func()
+}
+
+// NewSuite creates a suite running every function warmUp times unmeasured, then runs times measured
+func NewSuite(runs, warmUp int) *Suite {
+	if runs < 1 {
+		runs = 1
+	}
+	return &Suite{runs: runs, warmUp: warmUp}
+}
+
+// Add registers a function under the name, functions run in the order they were added
+func (s *Suite) Add(name string, fn func()) {
+	s.names = append(s.names, name)
+	s.funcs = append(s.funcs, fn)
+}
+
+// SuiteResult is the timing of a suite function
+type SuiteResult struct {
+	Name   string        `json:"name"`
+	Runs   int           `json:"runs"`
+	Total  time.Duration `json:"total_ns"`
+	Min    time.Duration `json:"min_ns"`
+	Max    time.Duration `json:"max_ns"`
+	Mean   time.Duration `json:"mean_ns"`
+	Median time.Duration `json:"median_ns"`
+	P90    time.Duration `json:"p90_ns"`
+	// Relative is the mean divided by the mean of the fastest function
+	Relative float64 `json:"relative"`
+}
+
+// SuiteResults are results of Suite.Run in the order functions were added
+type SuiteResults []SuiteResult
+
+// Run runs all functions, timing every measured run. Runs are timed directly rather than
+// by a stopwatch, so environment defaults, sampling or caps can't lose them.
+func (s *Suite) Run() SuiteResults {
+	results := make(SuiteResults, len(s.funcs))
+	for i, fn := range s.funcs {
+		for run := 0; run < s.warmUp; run++ {
+			fn()
+		}
+
+		durations := make([]time.Duration, s.runs)
+		for run := range durations {
+			start := time.Now()
+			fn()
+			durations[run] = time.Since(start)
+		}
+		results[i] = newSuiteResult(s.names[i], durations)
+	}
+
+	var fastest time.Duration
+	for _, result := range results {
+		if fastest == 0 || result.Mean < fastest {
+			fastest = result.Mean
+		}
+	}
+	for i := range results {
+		if fastest > 0 {
+			results[i].Relative = float64(results[i].Mean) / float64(fastest)
+		}
+	}
+	return results
+}
+
+// newSuiteResult sorts the durations
+func newSuiteResult(name string, durations []time.Duration) SuiteResult {
+	result := SuiteResult{Name: name, Runs: len(durations)}
+	if len(durations) == 0 {
+		return result
+	}
+	for _, d := range durations {
+		result.Total += d
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	result.Min = durations[0]
+	result.Max = durations[len(durations)-1]
+	result.Mean = result.Total / time.Duration(len(durations))
+	result.Median = nearestRank(durations, 50)
+	result.P90 = nearestRank(durations, 90)
+	return result
+}
+
+// nearestRank returns the percentile of sorted durations by the nearest-rank method
+func nearestRank(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (r SuiteResults) rows() [][]string {
+	rows := [][]string{{"NAME", "RUNS", "MEAN", "MEDIAN", "P90", "MIN", "MAX", "RELATIVE"}}
+	for _, result := range r {
+		rows = append(rows, []string{
+			result.Name,
+			fmt.Sprint(result.Runs),
+			result.Mean.String(),
+			result.Median.String(),
+			result.P90.String(),
+			result.Min.String(),
+			result.Max.String(),
+			fmt.Sprintf("%.2fx", result.Relative),
+		})
+	}
+	return rows
+}
+
+// WriteTable writes results as an aligned text table
+func (r SuiteResults) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range r.rows() {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown writes results as a Markdown table
+func (r SuiteResults) WriteMarkdown(w io.Writer) error {
+	rows := r.rows()
+	separator := make([]string, len(rows[0]))
+	separator[0] = "---"
+	for i := 1; i < len(separator); i++ {
+		separator[i] = "---:"
+	}
+	rows = append(rows[:1], append([][]string{separator}, rows[1:]...)...)
+
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes results as a JSON array
+func (r SuiteResults) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
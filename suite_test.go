@@ -0,0 +1,77 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite(t *testing.T) {
+	calls := map[string]int{}
+	suite := NewSuite(5, 2)
+	suite.Add("fast", func() { calls["fast"]++ })
+	suite.Add("slow", func() {
+		calls["slow"]++
+		time.Sleep(time.Millisecond)
+	})
+
+	results := suite.Run()
+	assert.Equal(t, map[string]int{"fast": 7, "slow": 7}, calls)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "fast", results[0].Name)
+		assert.Equal(t, 5, results[1].Runs)
+		assert.Equal(t, 1.0, results[0].Relative)
+		assert.True(t, results[1].Relative > 1)
+		assert.True(t, results[1].Min >= time.Millisecond)
+		assert.True(t, results[1].Min <= results[1].Median && results[1].Median <= results[1].P90 && results[1].P90 <= results[1].Max)
+	}
+}
+
+func TestSuiteResultsOutput(t *testing.T) {
+	results := SuiteResults{
+		{Name: "a", Runs: 2, Mean: time.Millisecond, Median: time.Millisecond, P90: time.Millisecond, Min: time.Millisecond, Max: time.Millisecond, Relative: 1},
+		{Name: "bb", Runs: 2, Mean: 2 * time.Millisecond, Median: 2 * time.Millisecond, P90: 3 * time.Millisecond, Min: time.Millisecond, Max: 3 * time.Millisecond, Relative: 2},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, results.WriteTable(&buf))
+	assert.Equal(t, strings.Join([]string{
+		"NAME  RUNS  MEAN  MEDIAN  P90  MIN  MAX  RELATIVE",
+		"a     2     1ms   1ms     1ms  1ms  1ms  1.00x",
+		"bb    2     2ms   2ms     3ms  1ms  3ms  2.00x",
+		"",
+	}, "\n"), buf.String())
+
+	buf.Reset()
+	assert.NoError(t, results.WriteMarkdown(&buf))
+	assert.Equal(t, strings.Join([]string{
+		"| NAME | RUNS | MEAN | MEDIAN | P90 | MIN | MAX | RELATIVE |",
+		"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |",
+		"| a | 2 | 1ms | 1ms | 1ms | 1ms | 1ms | 1.00x |",
+		"| bb | 2 | 2ms | 2ms | 3ms | 1ms | 3ms | 2.00x |",
+		"",
+	}, "\n"), buf.String())
+
+	buf.Reset()
+	assert.NoError(t, results.WriteJSON(&buf))
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, 3e6, decoded[1]["p90_ns"])
+}
+
+func TestNearestRank(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), nearestRank(sorted, 50))
+	assert.Equal(t, time.Duration(9), nearestRank(sorted, 90))
+	assert.Equal(t, time.Duration(1), nearestRank(sorted[:1], 90))
+}
+
+func TestSuiteResultWithoutRuns(t *testing.T) {
+	assert.NotPanics(t, func() {
+		assert.Equal(t, SuiteResult{Name: "empty"}, newSuiteResult("empty", nil))
+	})
+}
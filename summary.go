@@ -0,0 +1,133 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// StateSummary is the statistics of laps with a state, see Summary
+type StateSummary struct {
+	State string
+	// Count, Total, Min, Max and Mean are of all laps since the last Reset,
+	// including laps not kept because of sampling or the MaxLaps limit, like StateCounts
+	Count                 int
+	Total, Min, Max, Mean time.Duration
+	// Median, P90 and P99 are of kept laps only, zero if none are kept
+	Median, P90, P99 time.Duration
+}
+
+// Summary returns statistics of laps per state, sorted by state, so a state lapped
+// in a loop is reported as totals rather than every lap. Totals are updated as laps
+// are recorded, percentiles cost a sort of kept laps.
+func (s *Stopwatch) Summary() []StateSummary {
+	s.rlock()
+	defer s.runlock()
+	return s.summaryLocked()
+}
+
+// summaryLocked must be called under the read lock
+func (s *Stopwatch) summaryLocked() []StateSummary {
+	durations := make(map[string][]time.Duration, len(s.counts))
+	for _, lap := range s.laps {
+		durations[lap.state] = append(durations[lap.state], lap.duration)
+	}
+
+	counts := s.counts
+	copied := false
+	for state, laps := range durations {
+		if _, found := counts[state]; found {
+			continue
+		}
+		// laps loaded with LoadFrom or UnmarshalJSON are not counted
+		if !copied {
+			counts = make(map[string]stateStats, len(durations))
+			for state, stats := range s.counts {
+				counts[state] = stats
+			}
+			copied = true
+		}
+		var stats stateStats
+		for _, d := range laps {
+			stats.add(d)
+		}
+		counts[state] = stats
+	}
+
+	summary := make([]StateSummary, 0, len(counts))
+	for state, stats := range counts {
+		item := StateSummary{
+			State: state,
+			Count: stats.count,
+			Total: stats.total,
+			Min:   stats.min,
+			Max:   stats.max,
+			Mean:  stats.total / time.Duration(stats.count),
+		}
+		if sorted := durations[state]; len(sorted) > 0 {
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			item.Median = nearestRank(sorted, 50)
+			item.P90 = nearestRank(sorted, 90)
+			item.P99 = nearestRank(sorted, 99)
+		}
+		summary = append(summary, item)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].State < summary[j].State })
+	return summary
+}
+
+type summaryJSON struct {
+	State    string  `json:"state"`
+	Count    int     `json:"count"`
+	TotalMs  float64 `json:"total_ms"`
+	MinMs    float64 `json:"min_ms"`
+	MaxMs    float64 `json:"max_ms"`
+	MeanMs   float64 `json:"mean_ms"`
+	MedianMs float64 `json:"median_ms"`
+	P90Ms    float64 `json:"p90_ms"`
+	P99Ms    float64 `json:"p99_ms"`
+}
+
+// summaryTreeLocked is summaryLocked with statistics of children, their states prefixed
+// by child names like in treeLaps. It must be called under the read lock.
+func (s *Stopwatch) summaryTreeLocked() []StateSummary {
+	summary := s.summaryLocked()
+	if len(s.children) == 0 {
+		return summary
+	}
+	for _, child := range s.children {
+		child.sw.rlock()
+		for _, item := range child.sw.summaryTreeLocked() {
+			item.State = child.name + "." + item.State
+			summary = append(summary, item)
+		}
+		child.sw.runlock()
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].State < summary[j].State })
+	return summary
+}
+
+// formatSummary must be called under the read lock
+func (s *Stopwatch) formatSummary() (string, error) {
+	summary := s.summaryTreeLocked()
+	items := make([]summaryJSON, len(summary))
+	for i, item := range summary {
+		items[i] = summaryJSON{
+			State:    item.State,
+			Count:    item.Count,
+			TotalMs:  milliseconds(item.Total),
+			MinMs:    milliseconds(item.Min),
+			MaxMs:    milliseconds(item.Max),
+			MeanMs:   milliseconds(item.Mean),
+			MedianMs: milliseconds(item.Median),
+			P90Ms:    milliseconds(item.P90),
+			P99Ms:    milliseconds(item.P99),
+		}
+	}
+
+	result, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
@@ -0,0 +1,38 @@
+package stopwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	sw := New(0, false)
+	sw.SetMaxLaps(3)
+	for _, d := range []time.Duration{4, 1, 2, 3} {
+		sw.countLap(Lap{state: "db", duration: d * time.Millisecond})
+		sw.laps = append(sw.laps, Lap{state: "db", duration: d * time.Millisecond})
+	}
+	sw.laps = sw.laps[1:] // evicted
+	sw.laps = append(sw.laps, Lap{state: "cache", duration: time.Millisecond})
+
+	assert.Equal(t, []StateSummary{
+		{State: "cache", Count: 1, Total: time.Millisecond, Min: time.Millisecond, Max: time.Millisecond,
+			Mean: time.Millisecond, Median: time.Millisecond, P90: time.Millisecond, P99: time.Millisecond},
+		{State: "db", Count: 4, Total: 10 * time.Millisecond, Min: time.Millisecond, Max: 4 * time.Millisecond,
+			Mean: 2500 * time.Microsecond, Median: 2 * time.Millisecond, P90: 3 * time.Millisecond, P99: 3 * time.Millisecond},
+	}, sw.Summary())
+}
+
+func TestFormatSummary(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("db")
+	sw.Lap("db")
+	sw.SetFormattingMode(FormattingModeJsonSummary)
+	assert.Contains(t, sw.String(), `[{"state":"db","count":2,"total_ms":`)
+	assert.NoError(t, sw.ValidateOutput(FormattingModeJsonSummary))
+
+	sw.Reset(0, true)
+	assert.Equal(t, "[]", sw.String())
+}
@@ -0,0 +1,76 @@
+package stopwatch
+
+import (
+	"bytes"
+	"errors"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the data model of templates, see SetTemplate
+type TemplateData struct {
+	CorrelationID string
+	RunID         string
+	Running       bool
+	StartedAt     time.Time
+	Elapsed       time.Duration
+	Laps          []TemplateLap
+	// States are lap states in order of their first lap, Totals are durations of their laps
+	States []string
+	Totals map[string]time.Duration
+	// Rollups are totals of every level of dotted states, see Stopwatch.Rollups
+	Rollups []Rollup
+}
+
+// TemplateLap is a lap in TemplateData
+type TemplateLap struct {
+	State    string
+	Offset   time.Duration
+	Duration time.Duration
+	Data     map[string]interface{}
+}
+
+// SetTemplate parses a text/template and switches the stopwatch to FormattingModeTemplate,
+// for bespoke log formats. Templates get TemplateData, and the "ms" function converting
+// a duration to float milliseconds:
+//
+//	sw.SetTemplate(`{{range .Laps}}{{.State}}={{ms .Duration | printf "%.1f"}}ms {{end}}`)
+func (s *Stopwatch) SetTemplate(text string) error {
+	tmpl, err := template.New("stopwatch").Funcs(template.FuncMap{"ms": milliseconds}).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	s.lock()
+	defer s.unlock()
+	s.template = tmpl
+	s.formattingMode = FormattingModeTemplate
+	return nil
+}
+
+// formatTemplate must be called under the read lock
+func (s *Stopwatch) formatTemplate() (string, error) {
+	if s.template == nil {
+		return "", errors.New("no template set, see SetTemplate")
+	}
+
+	data := TemplateData{
+		CorrelationID: s.correlationID,
+		RunID:         s.runID,
+		Running:       s.active(),
+		StartedAt:     s.startedAtLocked(),
+		Elapsed:       s.ElapsedTime(),
+		Laps:          make([]TemplateLap, len(s.laps)),
+	}
+	for i, lap := range s.laps {
+		data.Laps[i] = TemplateLap{State: lap.state, Offset: lap.offset, Duration: lap.duration, Data: lap.data}
+	}
+	data.States, data.Totals = stateTotals(s.laps)
+	data.Rollups = rollups(s.laps)
+
+	var buf bytes.Buffer
+	if err := s.template.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,47 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTemplate(t *testing.T) {
+	sw := New(0, false)
+	sw.stop = sw.start.Add(30 * time.Millisecond)
+	sw.laps = []Lap{
+		{state: "db", duration: 10 * time.Millisecond, data: map[string]interface{}{"rows": 2}},
+		{state: "render", offset: 10 * time.Millisecond, duration: 5 * time.Millisecond},
+		{state: "db", offset: 15 * time.Millisecond, duration: 15 * time.Millisecond},
+	}
+	sw.SetCorrelationID("req-1")
+
+	err := sw.SetTemplate(`{{.CorrelationID}} total={{.Elapsed}}{{range .Laps}} {{.State}}={{ms .Duration | printf "%.1f"}}{{with .Data}}{{.rows}}{{end}}{{end}}` +
+		`{{range .States}} sum.{{.}}={{index $.Totals .}}{{end}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "req-1 total=30ms db=10.02 render=5.0 db=15.0 sum.db=25ms sum.render=5ms", sw.String())
+}
+
+func TestSetTemplateErrors(t *testing.T) {
+	sw := New(0, true)
+	assert.Error(t, sw.SetTemplate(`{{.Laps`))
+
+	sw.SetFormattingMode(FormattingModeTemplate)
+	assert.Equal(t, `{"error":"no template set, see SetTemplate"}`, sw.String())
+
+	assert.NoError(t, sw.SetTemplate(`{{.Unknown}}`))
+	assert.Contains(t, sw.String(), `"error"`)
+}
+
+func TestTemplateMarshalJSON(t *testing.T) {
+	sw := New(0, true)
+	sw.Lap("db")
+	assert.NoError(t, sw.SetTemplate(`{{range .Laps}}{{.State}} {{end}}`))
+	assert.Equal(t, "db ", sw.String())
+
+	encoded, err := json.Marshal(map[string]interface{}{"timings": sw})
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"laps":[{"state":"db"`)
+}
@@ -0,0 +1,74 @@
+package stopwatch
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOption configures the stopwatch created by ForTest
+type TestOption func(*testConfig)
+
+type testConfig struct {
+	logAlways bool
+}
+
+// TestLogAlways makes ForTest dump the laps even if the test passed.
+// By default the laps are logged only for failed tests.
+func TestLogAlways() TestOption {
+	return func(c *testConfig) {
+		c.logAlways = true
+	}
+}
+
+// ForTest creates an active stopwatch bound to the test. When the test
+// finishes, the stopwatch is stopped and its laps are logged through t.Logf.
+func ForTest(t testing.TB, opts ...TestOption) *Stopwatch {
+	t.Helper()
+
+	var cfg testConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sw := New(0, true)
+	t.Cleanup(func() {
+		sw.Stop()
+		if cfg.logAlways || t.Failed() {
+			t.Logf("stopwatch: elapsed %s, laps %s", sw.ElapsedTime(), sw.String())
+		}
+	})
+	return sw
+}
+
+// ReportToBenchmark reports the total time of every lap state divided by b.N
+// as a custom benchmark metric, e.g. "db-ns/op". Call it after the benchmark loop.
+func (s *Stopwatch) ReportToBenchmark(b *testing.B) {
+	b.Helper()
+	if b.N == 0 {
+		return
+	}
+
+	states, totals := stateTotals(s.Laps())
+	for _, state := range states {
+		b.ReportMetric(float64(totals[state].Nanoseconds())/float64(b.N), metricUnit(state)+"-ns/op")
+	}
+}
+
+// stateTotals sums up lap durations per state. States are returned in order of first appearance.
+func stateTotals(laps []Lap) ([]string, map[string]time.Duration) {
+	var states []string
+	totals := make(map[string]time.Duration)
+	for _, lap := range laps {
+		if _, found := totals[lap.state]; !found {
+			states = append(states, lap.state)
+		}
+		totals[lap.state] += lap.duration
+	}
+	return states, totals
+}
+
+// metricUnit makes a state usable as a benchmark unit, which must not contain whitespace
+func metricUnit(state string) string {
+	return strings.Join(strings.Fields(state), "_")
+}
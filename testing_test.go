@@ -0,0 +1,84 @@
+package stopwatch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTB struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Failed() bool      { return f.failed }
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestForTestLogsOnFailure(t *testing.T) {
+	tb := &fakeTB{}
+	sw := ForTest(tb)
+	sw.Lap("setup")
+	tb.failed = true
+	tb.finish()
+
+	assert.Len(t, tb.logs, 1)
+	assert.Contains(t, tb.logs[0], `"state":"setup"`)
+}
+
+func TestForTestSilentOnSuccess(t *testing.T) {
+	tb := &fakeTB{}
+	sw := ForTest(tb)
+	sw.Lap("setup")
+	tb.finish()
+
+	assert.Empty(t, tb.logs)
+}
+
+func TestForTestLogAlways(t *testing.T) {
+	tb := &fakeTB{}
+	sw := ForTest(tb, TestLogAlways())
+	sw.Lap("setup")
+	tb.finish()
+
+	assert.Len(t, tb.logs, 1)
+}
+
+func TestStateTotals(t *testing.T) {
+	laps := []Lap{
+		{state: "db", duration: 10},
+		{state: "render", duration: 5},
+		{state: "db", duration: 20},
+	}
+
+	states, totals := stateTotals(laps)
+	assert.Equal(t, []string{"db", "render"}, states)
+	assert.Equal(t, time.Duration(30), totals["db"])
+	assert.Equal(t, time.Duration(5), totals["render"])
+}
+
+func TestMetricUnit(t *testing.T) {
+	assert.Equal(t, "Create_File", metricUnit("Create File"))
+}
+
+func BenchmarkReportToBenchmark(b *testing.B) {
+	sw := New(0, true)
+	for i := 0; i < b.N; i++ {
+		sw.Lap("first")
+		sw.Lap("second")
+	}
+	sw.ReportToBenchmark(b)
+}
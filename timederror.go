@@ -0,0 +1,55 @@
+package stopwatch
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimedError is an error annotated with the stopwatch state when it happened,
+// get it with errors.As
+type TimedError struct {
+	Err error
+	// Elapsed is the elapsed time of the stopwatch
+	Elapsed time.Duration
+	// LapTime is the time since the last lap
+	LapTime time.Duration
+	// State is the state of the last recorded lap, empty before the first lap
+	State string
+}
+
+func (e *TimedError) Error() string {
+	if e.State == "" {
+		return fmt.Sprintf("%v (after %v)", e.Err, e.Elapsed)
+	}
+	return fmt.Sprintf("%v (after %v, %v since %q)", e.Err, e.Elapsed, e.LapTime, e.State)
+}
+
+// Unwrap returns the annotated error
+func (e *TimedError) Unwrap() error {
+	return e.Err
+}
+
+// WrapError annotates err with the elapsed time and the last lap, see TimedError.
+// Nil stays nil.
+func (s *Stopwatch) WrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s.rlock()
+	defer s.runlock()
+	timed := &TimedError{
+		Err:     err,
+		Elapsed: s.ElapsedTime(),
+	}
+	timed.LapTime = timed.Elapsed - s.mark
+	if len(s.laps) > 0 {
+		timed.State = s.laps[len(s.laps)-1].state
+	}
+	return timed
+}
+
+// Errorf formats an error as fmt.Errorf does, %w included, and annotates it with WrapError
+func (s *Stopwatch) Errorf(format string, args ...interface{}) error {
+	return s.WrapError(fmt.Errorf(format, args...))
+}
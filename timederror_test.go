@@ -0,0 +1,29 @@
+package stopwatch
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapError(t *testing.T) {
+	sw := New(0, false)
+	sw.stop = sw.start.Add(30 * time.Millisecond)
+	assert.Nil(t, sw.WrapError(nil))
+	assert.EqualError(t, sw.WrapError(io.EOF), "EOF (after 30ms)")
+
+	sw.laps = []Lap{{state: "connect", duration: 10 * time.Millisecond}}
+	sw.mark = 10 * time.Millisecond
+	err := sw.Errorf("query: %w", io.EOF)
+	assert.EqualError(t, err, `query: EOF (after 30ms, 20ms since "connect")`)
+	assert.True(t, errors.Is(err, io.EOF))
+
+	var timed *TimedError
+	assert.True(t, errors.As(err, &timed))
+	assert.Equal(t, 30*time.Millisecond, timed.Elapsed)
+	assert.Equal(t, 20*time.Millisecond, timed.LapTime)
+	assert.Equal(t, "connect", timed.State)
+}
@@ -0,0 +1,68 @@
+package stopwatch
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TruncationMarker ends states and data values cut by SetTruncation
+const TruncationMarker = "…"
+
+// SetTruncation limits the length in bytes of lap states and of string data values,
+// longer ones are cut and end with TruncationMarker. Strings, byte slices, errors and
+// fmt.Stringer values count as strings. Values added by the stopwatch, like goroutine dumps
+// of SetSlowLapProfile, are truncated too. It keeps a caller passing a huge SQL query
+// as a lap state from flooding logs. Zero turns a limit off.
+func (s *Stopwatch) SetTruncation(maxState, maxValue int) {
+	s.lock()
+	defer s.unlock()
+	s.maxState = maxState
+	s.maxValue = maxValue
+}
+
+// truncateLap must be called under the lock, the data map is copied if changed
+func (s *Stopwatch) truncateLap(state string, data map[string]interface{}) (string, map[string]interface{}) {
+	if s.maxState > 0 {
+		state = truncate(state, s.maxState)
+	}
+	if s.maxValue <= 0 {
+		return state, data
+	}
+
+	copied := false
+	for k, v := range data {
+		var str string
+		switch v := v.(type) {
+		case string:
+			str = v
+		case []byte:
+			str = string(v)
+		case error:
+			str = v.Error()
+		case fmt.Stringer:
+			str = v.String()
+		default:
+			continue
+		}
+		if len(str) <= s.maxValue {
+			continue
+		}
+		if !copied {
+			data = copyData(data, 0)
+			copied = true
+		}
+		data[k] = truncate(str, s.maxValue)
+	}
+	return state, data
+}
+
+// truncate cuts s to max bytes without splitting a rune
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + TruncationMarker
+}
@@ -0,0 +1,54 @@
+package stopwatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncation(t *testing.T) {
+	sw := New(0, true)
+	sw.SetTruncation(6, 4)
+
+	data := map[string]interface{}{
+		"query": "SELECT 1",
+		"err":   errors.New("broken pipe"),
+		"raw":   []byte("abc"),
+		"rows":  123456789,
+	}
+	lap := sw.LapWithData("SELECT * FROM users", data)
+
+	assert.Equal(t, "SELECT"+TruncationMarker, lap.State())
+	assert.Equal(t, map[string]interface{}{
+		"query": "SELE" + TruncationMarker,
+		"err":   "brok" + TruncationMarker,
+		"raw":   []byte("abc"),
+		"rows":  123456789,
+	}, lap.data)
+	assert.Equal(t, "SELECT 1", data["query"], "caller's data is not modified")
+
+	sw.SetTruncation(0, 0)
+	assert.Equal(t, "SELECT * FROM users", sw.Lap("SELECT * FROM users").State())
+}
+
+func TestTruncationGoroutineDump(t *testing.T) {
+	sw := New(0, false)
+	sw.SetTruncation(0, 100)
+	sw.SetSlowLapProfile(time.Millisecond, true)
+
+	sw.stop = sw.start.Add(10 * time.Millisecond)
+	lap := sw.Lap("slow")
+	dump := lap.data[GoroutineDumpKey].(string)
+	assert.True(t, strings.HasPrefix(dump, "goroutine "))
+	assert.Equal(t, 100+len(TruncationMarker), len(dump))
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	assert.Equal(t, "ab"+TruncationMarker, truncate("abвг", 3))
+	assert.Equal(t, "abв"+TruncationMarker, truncate("abвг", 4))
+	assert.Equal(t, "abвг", truncate("abвг", 6))
+	assert.Equal(t, strings.Repeat("x", 10)+TruncationMarker, truncate(strings.Repeat("x", 20), 10))
+}
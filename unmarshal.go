@@ -0,0 +1,174 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UnmarshalJSON restores a stopwatch serialized in FormattingModeJsonFull, FormattingModeJsonDetailed
+// or FormattingModeJsonArray, keeping settings like the formatting mode. Times of the array mode
+// must be Go durations, as written by the default formatter. The object modes can't be restored,
+// they don't keep lap data. The stopwatch is stopped at the end of its last lap, unless
+// the full mode tells when it was stopped. Lap data go through JSON, so numbers come back as float64.
+func (s *Stopwatch) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	var laps []Lap
+	var full FullStopwatch
+	switch {
+	case bytes.HasPrefix(data, []byte("{")):
+		if err := json.Unmarshal(data, &full); err != nil {
+			return err
+		}
+		if full.Laps == nil {
+			return fmt.Errorf("stopwatch: no laps, only %s can be restored from an object", FormattingModeJsonFull)
+		}
+		laps = detailedLaps(full.Laps)
+	case bytes.HasPrefix(data, []byte("[")):
+		var err error
+		if laps, err = unmarshalLaps(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("stopwatch: expected an array or an object of laps")
+	}
+
+	if s.formatter == nil { // a zero Stopwatch, not created with New
+		s.SetFormatter(defaultFormatter)
+		s.SetPrecision(defaultPrecision)
+		s.SetIntegerUnit(defaultIntegerUnit)
+	}
+	s.Reset(0, false)
+
+	s.lock()
+	defer s.unlock()
+	var mark time.Duration
+	for i := range laps {
+		laps[i].formatter = s.formatter
+		mark = laps[i].offset + laps[i].duration
+		if laps[i].seq > s.seq {
+			s.seq = laps[i].seq
+		}
+		if laps[i].runID != "" {
+			s.runID = laps[i].runID
+		}
+		if laps[i].correlationID != "" {
+			s.correlationID = laps[i].correlationID
+		}
+	}
+	s.mark = mark
+
+	elapsed := mark
+	if full.ElapsedMs > 0 {
+		elapsed = time.Duration(full.ElapsedMs * float64(time.Millisecond)).Round(time.Microsecond)
+		s.paused = time.Duration(full.PausedMs * float64(time.Millisecond)).Round(time.Microsecond)
+		s.adjusted = time.Duration(full.AdjustedMs * float64(time.Millisecond)).Round(time.Microsecond)
+	}
+	if full.CorrelationID != "" {
+		s.correlationID = full.CorrelationID
+	}
+	if full.RunID != "" {
+		s.runID = full.RunID
+	}
+	if !full.StartedAt.IsZero() {
+		s.start = full.StartedAt.Add(s.paused - s.adjusted)
+		s.stop = s.start.Add(elapsed)
+		if full.StoppedAt != nil {
+			s.stop = *full.StoppedAt
+		}
+	} else {
+		s.start = s.stop.Add(-elapsed)
+	}
+	s.activeSince = s.start
+	for _, pause := range full.Pauses {
+		restored := Pause{Reason: pause.Reason, Start: pause.StartedAt}
+		if pause.EndedAt != nil {
+			restored.End = *pause.EndedAt
+		}
+		s.pauses = append(s.pauses, restored)
+	}
+
+	for i := range laps {
+		laps[i].end = s.start.Add(laps[i].offset + laps[i].duration)
+		if laps[i].correlationID == "" {
+			laps[i].correlationID = s.correlationID
+		}
+		if laps[i].runID == "" && full.RunID != "" {
+			laps[i].runID = full.RunID
+		}
+		s.laps = append(s.laps, laps[i])
+		s.indexLap(laps[i])
+	}
+	s.recapLocked()
+	return nil
+}
+
+func detailedLaps(detailed []DetailedLap) []Lap {
+	laps := make([]Lap, len(detailed))
+	for i, lap := range detailed {
+		laps[i] = Lap{
+			state:         lap.State,
+			offset:        time.Duration(lap.OffsetMs * float64(time.Millisecond)).Round(time.Microsecond),
+			duration:      time.Duration(lap.Ms * float64(time.Millisecond)).Round(time.Microsecond),
+			data:          lap.Data,
+			correlationID: lap.CorrelationID,
+			seq:           lap.Seq,
+			runID:         lap.RunID,
+		}
+	}
+	return laps
+}
+
+// unmarshalLaps reads laps of FormattingModeJsonDetailed or FormattingModeJsonArray
+func unmarshalLaps(data []byte) ([]Lap, error) {
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	if len(items) > 0 {
+		if _, found := items[0]["ms"]; found {
+			var detailed []DetailedLap
+			if err := json.Unmarshal(data, &detailed); err != nil {
+				return nil, err
+			}
+			return detailedLaps(detailed), nil
+		}
+	}
+
+	laps := make([]Lap, len(items))
+	var offset time.Duration
+	for i, item := range items {
+		var state, formatted string
+		if err := json.Unmarshal(item["state"], &state); err != nil {
+			return nil, fmt.Errorf("stopwatch: lap %d: state: %w", i, err)
+		}
+		if err := json.Unmarshal(item["time"], &formatted); err != nil {
+			return nil, fmt.Errorf("stopwatch: lap %d %q: time: %w", i, state, err)
+		}
+		duration, err := time.ParseDuration(formatted)
+		if err != nil {
+			return nil, fmt.Errorf("stopwatch: lap %d %q: %w", i, state, err)
+		}
+		laps[i] = Lap{state: state, offset: offset, duration: duration}
+		offset += duration
+
+		for key, raw := range item {
+			var value interface{}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, err
+			}
+			switch key {
+			case "state", "time":
+			case CorrelationIDKey:
+				laps[i].correlationID, _ = value.(string)
+			default:
+				if laps[i].data == nil {
+					laps[i].data = make(map[string]interface{}, len(item))
+				}
+				laps[i].data[key] = value
+			}
+		}
+	}
+	return laps, nil
+}
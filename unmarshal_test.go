@@ -0,0 +1,56 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newUnmarshalStopwatch() *Stopwatch {
+	sw := New(0, false)
+	sw.SetCorrelationID("req-1")
+	sw.laps = []Lap{
+		{formatter: defaultFormatter, state: `say "hi"`, duration: time.Millisecond, data: map[string]interface{}{"path": `C:\temp`}, correlationID: "req-1"},
+		{formatter: defaultFormatter, state: "render", offset: time.Millisecond, duration: 2 * time.Millisecond, correlationID: "req-1"},
+	}
+	sw.stop = sw.start.Add(3 * time.Millisecond)
+	return sw
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	for _, mode := range []FormattingMode{FormattingModeJsonArray, FormattingModeJsonDetailed, FormattingModeJsonFull} {
+		sw := newUnmarshalStopwatch()
+		sw.SetFormattingMode(mode)
+		encoded, err := json.Marshal(sw)
+		assert.NoError(t, err, mode)
+
+		restored := New(0, true)
+		restored.SetFormattingMode(mode)
+		assert.NoError(t, json.Unmarshal(encoded, restored), mode)
+		assert.False(t, restored.active(), mode)
+		assert.Equal(t, 3*time.Millisecond, restored.ElapsedTime(), mode)
+
+		laps := restored.Laps()
+		if assert.Len(t, laps, 2, mode) {
+			assert.Equal(t, `say "hi"`, laps[0].State(), mode)
+			assert.Equal(t, map[string]interface{}{"path": `C:\temp`}, laps[0].Data(), mode)
+			assert.Equal(t, "req-1", laps[0].CorrelationID(), mode)
+			assert.Equal(t, 2*time.Millisecond, laps[1].Duration(), mode)
+			assert.Equal(t, time.Millisecond, laps[1].StartOffset(), mode)
+		}
+		assert.Equal(t, sw.String(), restored.String(), mode)
+	}
+}
+
+func TestUnmarshalJSONZeroStopwatch(t *testing.T) {
+	var sw Stopwatch
+	assert.NoError(t, json.Unmarshal([]byte(`[{"state":"db", "time":"1.5ms"}]`), &sw))
+	assert.Equal(t, `[{"state":"db", "time":"1.5ms"}]`, sw.String())
+}
+
+func TestUnmarshalJSONObjectMode(t *testing.T) {
+	sw := New(0, false)
+	assert.Error(t, json.Unmarshal([]byte(`{"db":"1ms"}`), sw))
+}
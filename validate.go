@@ -0,0 +1,61 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOutput is wrapped by errors of ValidateOutput
+var ErrInvalidOutput = errors.New("invalid output")
+
+// ValidateOutput checks that the stopwatch would be formatted in the mode as valid JSON,
+// so broken log lines are caught in tests or at startup rather than in the log pipeline.
+// Errors name the lap and the data key at fault, e.g. a NaN float or a channel in lap data.
+// Templates are only executed.
+func (s *Stopwatch) ValidateOutput(mode FormattingMode) error {
+	mode = defaultedFormattingMode(mode)
+	// the array and object modes write lap data with fmt or leave it out, like the summary
+	marshalsData := mode != FormattingModeJsonArray && mode != FormattingModeJsonSimpleObject &&
+		mode != FormattingModeJsonMsObject && mode != FormattingModeJsonIntObject && mode != FormattingModeJsonSummary
+
+	if marshalsData {
+		s.rlock()
+		for i, lap := range s.laps {
+			if err := validateLap(lap); err != nil {
+				s.runlock()
+				return fmt.Errorf("%w: lap %d %q: %v", ErrInvalidOutput, i, lap.state, err)
+			}
+		}
+		s.runlock()
+	}
+
+	output, err := s.formatAs(mode)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	if mode == FormattingModeTemplate {
+		return nil
+	}
+
+	documents := []string{output}
+	if mode == FormattingModeNDJSON {
+		documents = strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	}
+	for _, document := range documents {
+		if document != "" && !json.Valid([]byte(document)) {
+			return fmt.Errorf("%w: %s output is not valid JSON", ErrInvalidOutput, mode)
+		}
+	}
+	return nil
+}
+
+func validateLap(lap Lap) error {
+	for key, value := range lap.data {
+		if _, err := json.Marshal(value); err != nil {
+			return fmt.Errorf("data %q: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package stopwatch
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOutput(t *testing.T) {
+	sw := New(0, false)
+	sw.laps = []Lap{
+		{formatter: defaultFormatter, state: "db", duration: time.Millisecond, data: map[string]interface{}{"rows": 2}},
+	}
+	for _, mode := range []FormattingMode{
+		FormattingModeJsonArray,
+		FormattingModeJsonSimpleObject,
+		FormattingModeJsonDetailed,
+		FormattingModeNDJSON,
+		FormattingModeJsonFull,
+		FormattingModeECS,
+		FormattingModeFlat,
+	} {
+		assert.NoError(t, sw.ValidateOutput(mode), mode)
+	}
+
+	sw.laps[0].data["ratio"] = math.NaN()
+	err := sw.ValidateOutput(FormattingModeJsonDetailed)
+	assert.True(t, errors.Is(err, ErrInvalidOutput))
+	assert.EqualError(t, err, `invalid output: lap 0 "db": data "ratio": json: unsupported value: NaN`)
+	assert.NoError(t, sw.ValidateOutput(FormattingModeJsonArray), "NaN is written as a string")
+	delete(sw.laps[0].data, "ratio")
+
+	sw.laps[0].data["note"] = `say "hi"`
+	sw.laps[0].state = "C:\\temp"
+	sw.SetCorrelationID("a\nb")
+	for _, mode := range []FormattingMode{
+		FormattingModeJsonArray,
+		FormattingModeJsonSimpleObject,
+		FormattingModeJsonMsObject,
+		FormattingModeJsonIntObject,
+		FormattingModeJsonDetailed,
+		FormattingModeJsonFull,
+	} {
+		assert.NoError(t, sw.ValidateOutput(mode), "%s escapes strings", mode)
+	}
+}
+
+func TestValidateOutputTemplate(t *testing.T) {
+	sw := New(0, true)
+	assert.NoError(t, sw.SetTemplate(`{{.Missing}}`))
+	assert.Error(t, sw.ValidateOutput(FormattingModeTemplate))
+	assert.NoError(t, sw.SetTemplate(`not JSON`))
+	assert.NoError(t, sw.ValidateOutput(FormattingModeTemplate))
+}
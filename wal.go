@@ -0,0 +1,122 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// writeAheadLog appends events to a file before they are applied to the stopwatch
+type writeAheadLog struct {
+	file *os.File
+	sync bool
+}
+
+func (w *writeAheadLog) write(events []Event) error {
+	var records []byte
+	for _, event := range events {
+		record, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		records = append(append(records, record...), '\n')
+	}
+	if _, err := w.file.Write(records); err != nil {
+		return err
+	}
+	if w.sync {
+		return w.file.Sync()
+	}
+	return nil
+}
+
+// OpenWAL returns a stopwatch in write-ahead log mode: every start, stop, lap and reset
+// is appended to the log at path before it is applied, so no interval is lost in a crash.
+// If the log exists, the stopwatch is recovered from it, see Replay, otherwise a new
+// running stopwatch is created. With sync every record is flushed to disk before it is applied.
+// A change that fails to be logged is not applied, see WALError. Close the stopwatch to close the log.
+func OpenWAL(path string, sync bool) (*Stopwatch, error) {
+	events, err := readWAL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sw *Stopwatch
+	if len(events) > 0 {
+		if sw, err = Replay(events); err != nil {
+			return nil, fmt.Errorf("recover %s: %w", path, err)
+		}
+	} else {
+		sw = New(0, true)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	wal := &writeAheadLog{file: file, sync: sync}
+	if len(events) == 0 {
+		if err := wal.write(currentEvents(sw.start, sw.stop, true)); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	sw.lock()
+	sw.wal = wal
+	sw.unlock()
+	return sw, nil
+}
+
+// readWAL reads events of the log. A record cut by a crash at the end of the log
+// was never applied, so it is removed.
+func readWAL(path string) ([]Event, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	complete := bytes.LastIndexByte(content, '\n') + 1
+	if complete < len(content) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, err
+		}
+	}
+
+	var events []Event
+	for i, line := range bytes.Split(content[:complete], []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("recover %s: record %d: %w", path, i+1, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// walWrite must be called under the lock, it reports whether the change may be applied
+func (s *Stopwatch) walWrite(events ...Event) bool {
+	if s.wal == nil {
+		return true
+	}
+	if err := s.wal.write(events); err != nil {
+		s.walErr = err
+		return false
+	}
+	return true
+}
+
+// WALError returns the last error writing the write-ahead log, see OpenWAL
+func (s *Stopwatch) WALError() error {
+	s.rlock()
+	defer s.runlock()
+	return s.walErr
+}
@@ -0,0 +1,97 @@
+package stopwatch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stopwatch.wal")
+
+	sw, err := OpenWAL(path, true)
+	assert.NoError(t, err)
+	sw.LapWithData("download", map[string]interface{}{"files": 2})
+	sw.Stop()
+	sw.Start()
+	sw.Lap("parse")
+	// the process crashes here, the log is not closed
+
+	recovered, err := OpenWAL(path, false)
+	assert.NoError(t, err)
+	defer recovered.Close(context.Background())
+	assert.True(t, recovered.active())
+	assert.True(t, Equal(sw, recovered, 100*time.Microsecond))
+
+	recovered.Lap("upload")
+	assert.NoError(t, recovered.Close(context.Background()))
+
+	again, err := OpenWAL(path, false)
+	assert.NoError(t, err)
+	defer again.Close(context.Background())
+	assert.False(t, again.active(), "Close logs the stop")
+	assert.True(t, Equal(recovered, again, 100*time.Microsecond))
+	assert.NoError(t, again.WALError())
+}
+
+func TestWALTruncatedRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stopwatch.wal")
+
+	sw, err := OpenWAL(path, false)
+	assert.NoError(t, err)
+	sw.Lap("lap1")
+	assert.NoError(t, sw.Close(context.Background()))
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"kind":"lap","ti`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	recovered, err := OpenWAL(path, false)
+	assert.NoError(t, err)
+	assert.Len(t, recovered.Laps(), 1)
+	recovered.Lap("lap2")
+	assert.NoError(t, recovered.Close(context.Background()))
+
+	again, err := OpenWAL(path, false)
+	assert.NoError(t, err, "the cut record is removed")
+	assert.Len(t, again.Laps(), 2)
+	assert.NoError(t, again.Close(context.Background()))
+}
+
+func TestWALWriteError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sw, err := OpenWAL(filepath.Join(dir, "stopwatch.wal"), false)
+	assert.NoError(t, err)
+	sw.wal.file.Close()
+
+	sw.Lap("lap1")
+	assert.Error(t, sw.WALError())
+	assert.Empty(t, sw.Laps(), "not logged, not applied")
+}
+
+func TestWALCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stopwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stopwatch.wal")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("garbage\n"), 0644))
+
+	_, err = OpenWAL(path, false)
+	assert.Error(t, err)
+}
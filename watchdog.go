@@ -0,0 +1,113 @@
+package stopwatch
+
+import (
+	"sync"
+	"time"
+)
+
+// Stall describes a running stopwatch without laps for longer than the watchdog timeout
+type Stall struct {
+	// LastState is the state of the last kept lap, empty if there are none
+	LastState string
+	// SinceLap is the time since the last lap, or since the start if there are no laps
+	SinceLap time.Duration
+	Elapsed  time.Duration
+}
+
+// watchdog checks the time since the last lap with a timer
+type watchdog struct {
+	sw *Stopwatch
+
+	mu       sync.Mutex
+	timeout  time.Duration
+	stalled  func(Stall)
+	timer    *time.Timer
+	gen      int // of the timer, checks of stopped timers are ignored
+	fired    bool
+	firedSeq uint64 // sequence number of the last lap when the watchdog fired
+}
+
+// SetWatchdog fires stalled when the running stopwatch records no lap for longer than
+// the timeout, so a hung pipeline gives a timing signal before it is killed.
+// It fires once per stall, the next lap rearms it. Time while the stopwatch
+// is stopped is not counted. Zero timeout or nil stalled turns it off.
+// A stopwatch WithoutLocking has no watchdog, its timer would read it from another goroutine.
+func (s *Stopwatch) SetWatchdog(timeout time.Duration, stalled func(Stall)) {
+	s.lock()
+	if s.noLocking {
+		s.unlock()
+		return
+	}
+	w := s.watchdog
+	if w == nil {
+		w = &watchdog{sw: s}
+		s.watchdog = w
+	}
+	s.unlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+	w.timeout = timeout
+	w.stalled = stalled
+	w.fired = false
+	if timeout > 0 && stalled != nil {
+		w.scheduleLocked(timeout)
+	}
+}
+
+// scheduleLocked must be called under w.mu
+func (w *watchdog) scheduleLocked(d time.Duration) {
+	gen := w.gen
+	w.timer = time.AfterFunc(d, func() { w.check(gen) })
+}
+
+func (w *watchdog) check(gen int) {
+	w.mu.Lock()
+	if w.stalled == nil || gen != w.gen {
+		w.mu.Unlock()
+		return
+	}
+
+	sw := w.sw
+	sw.rlock()
+	stall := Stall{Elapsed: sw.ElapsedTime(), SinceLap: sw.ElapsedTime() - sw.mark}
+	if len(sw.laps) > 0 {
+		stall.LastState = sw.laps[len(sw.laps)-1].state
+	}
+	seq, active := sw.seq, sw.active()
+	sw.runlock()
+
+	stalled := active && stall.SinceLap >= w.timeout && !(w.fired && seq == w.firedSeq)
+	next := w.timeout
+	if active && stall.SinceLap < w.timeout {
+		next = w.timeout - stall.SinceLap
+	}
+	w.scheduleLocked(next)
+	if !stalled {
+		w.mu.Unlock()
+		return
+	}
+	w.fired, w.firedSeq = true, seq
+	callback := w.stalled
+	w.mu.Unlock()
+
+	callback(stall)
+}
+
+// stop turns the watchdog off for good
+func (w *watchdog) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+	w.stalled = nil
+}
+
+// stopLocked must be called under w.mu
+func (w *watchdog) stopLocked() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.gen++
+}
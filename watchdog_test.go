@@ -0,0 +1,58 @@
+package stopwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog(t *testing.T) {
+	stalls := make(chan Stall, 10)
+	sw := New(0, true)
+	sw.Lap("load")
+	sw.SetWatchdog(20*time.Millisecond, func(stall Stall) { stalls <- stall })
+
+	select {
+	case stall := <-stalls:
+		assert.Equal(t, "load", stall.LastState)
+		assert.True(t, stall.SinceLap >= 20*time.Millisecond)
+		assert.True(t, stall.Elapsed >= stall.SinceLap)
+	case <-time.After(time.Second):
+		t.Fatal("watchdog didn't fire")
+	}
+
+	// once per stall
+	select {
+	case <-stalls:
+		t.Fatal("watchdog fired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the next lap rearms it
+	sw.Lap("transform")
+	select {
+	case stall := <-stalls:
+		assert.Equal(t, "transform", stall.LastState)
+	case <-time.After(time.Second):
+		t.Fatal("watchdog didn't fire after a lap")
+	}
+
+	// stopped stopwatch doesn't stall
+	sw.Lap("save")
+	sw.Stop()
+	select {
+	case <-stalls:
+		t.Fatal("watchdog fired for a stopped stopwatch")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	sw.Start()
+	assert.NoError(t, sw.Close(context.Background()))
+	select {
+	case <-stalls:
+		t.Fatal("watchdog fired after Close")
+	case <-time.After(50 * time.Millisecond):
+	}
+}